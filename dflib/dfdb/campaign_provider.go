@@ -0,0 +1,57 @@
+package dfdb
+
+import (
+	"io/fs"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// CampaignProvider defines the interface for chronicle, campaign and session storage backends.
+type CampaignProvider interface {
+	// CreateChronicle stores a new chronicle and returns an error if it fails.
+	CreateChronicle(chronicle dfm.Chronicle) error
+	// ReadChronicle retrieves a chronicle by ID, returning an error if not found.
+	ReadChronicle(chronicleID string) (dfm.Chronicle, error)
+	// UpdateChronicle modifies an existing chronicle, returning an error if it fails.
+	UpdateChronicle(chronicle dfm.Chronicle) error
+	// DeleteChronicle removes a chronicle by ID, returning an error if not found or it still has campaigns.
+	DeleteChronicle(chronicleID string) error
+	// ListChronicles returns chronicles matching the query filters, sorted by name.
+	ListChronicles(query dfm.ChronicleQuery) ([]dfm.Chronicle, error)
+	// ReadChronicleReadme returns the Markdown README of a chronicle, returning an error if not found.
+	ReadChronicleReadme(chronicleID string) (string, error)
+	// ListChronicleResources returns the downloadable resource files of a chronicle, sorted by name.
+	ListChronicleResources(chronicleID string) ([]dfm.Resource, error)
+	// OpenChronicleResource opens a resource file of a chronicle for reading, returning an error if not found.
+	OpenChronicleResource(chronicleID, name string) (fs.File, error)
+
+	// CreateCampaign stores a new campaign and returns an error if it or its chronicle is invalid.
+	CreateCampaign(campaign dfm.Campaign) error
+	// ReadCampaign retrieves a campaign by ID, returning an error if not found.
+	ReadCampaign(campaignID string) (dfm.Campaign, error)
+	// UpdateCampaign modifies an existing campaign, returning an error if it fails.
+	UpdateCampaign(campaign dfm.Campaign) error
+	// DeleteCampaign removes a campaign by ID, returning an error if not found or it still has sessions.
+	DeleteCampaign(campaignID string) error
+	// ListCampaigns returns campaigns matching the query filters, sorted by name.
+	ListCampaigns(query dfm.CampaignQuery) ([]dfm.Campaign, error)
+	// ReadCampaignReadme returns the Markdown README of a campaign, returning an error if not found.
+	ReadCampaignReadme(campaignID string) (string, error)
+	// ListCampaignResources returns the downloadable resource files of a campaign, sorted by name.
+	ListCampaignResources(campaignID string) ([]dfm.Resource, error)
+	// OpenCampaignResource opens a resource file of a campaign for reading, returning an error if not found.
+	OpenCampaignResource(campaignID, name string) (fs.File, error)
+
+	// CreateSession stores a new session and returns an error if it or its campaign is invalid.
+	CreateSession(session dfm.Session) error
+	// ReadSession retrieves a session by ID, returning an error if not found.
+	ReadSession(sessionID string) (dfm.Session, error)
+	// UpdateSession modifies an existing session, returning an error if it fails.
+	UpdateSession(session dfm.Session) error
+	// DeleteSession removes a session by ID, returning an error if not found.
+	DeleteSession(sessionID string) error
+	// ListSessions returns sessions matching the query filters, sorted by play time.
+	ListSessions(query dfm.SessionQuery) ([]dfm.Session, error)
+	// ReadSessionNotes returns the Markdown notes of a session, returning an error if not found.
+	ReadSessionNotes(sessionID string) (string, error)
+}
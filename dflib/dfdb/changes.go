@@ -0,0 +1,50 @@
+package dfdb
+
+import "sync"
+
+// changeEventBuffer is the number of change events buffered per subscriber before new events are dropped
+const changeEventBuffer = 64
+
+// changeFeed delivers character change events to subscribers.
+// Providers embed it to implement Subscribe. The zero value is ready to use.
+type changeFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan ChangeEvent]struct{}
+}
+
+// Subscribe returns a channel receiving character change events and a function
+// that cancels the subscription and closes the channel. Events are dropped for
+// subscribers that do not keep up.
+func (c *changeFeed) Subscribe() (<-chan ChangeEvent, func()) {
+	events := make(chan ChangeEvent, changeEventBuffer)
+
+	c.mu.Lock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[chan ChangeEvent]struct{})
+	}
+	c.subscribers[events] = struct{}{}
+	c.mu.Unlock()
+
+	unsubscribe := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.subscribers[events]; ok {
+			delete(c.subscribers, events)
+			close(events)
+		}
+	}
+	return events, unsubscribe
+}
+
+// notify delivers a change event to every subscriber without blocking
+func (c *changeFeed) notify(event ChangeEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for events := range c.subscribers {
+		select {
+		case events <- event:
+		default:
+			// Slow subscriber, drop the event rather than block the provider
+		}
+	}
+}
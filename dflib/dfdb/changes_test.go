@@ -0,0 +1,44 @@
+package dfdb
+
+import (
+	"testing"
+)
+
+func TestChangeFeed(t *testing.T) {
+	var feed changeFeed
+
+	first, unsubscribeFirst := feed.Subscribe()
+	second, unsubscribeSecond := feed.Subscribe()
+	defer unsubscribeSecond()
+
+	feed.notify(ChangeEvent{Type: ChangeCreated, CharacterID: "a"})
+	for _, events := range []<-chan ChangeEvent{first, second} {
+		if event := <-events; event.CharacterID != "a" {
+			t.Errorf("Expected event for a, got %+v", event)
+		}
+	}
+
+	// Unsubscribed channels are closed and receive nothing more
+	unsubscribeFirst()
+	feed.notify(ChangeEvent{Type: ChangeDeleted, CharacterID: "b"})
+	if _, ok := <-first; ok {
+		t.Error("Expected the first channel to be closed")
+	}
+	if event := <-second; event.CharacterID != "b" {
+		t.Errorf("Expected event for b, got %+v", event)
+	}
+}
+
+func TestChangeFeedDropsEventsForSlowSubscribers(t *testing.T) {
+	var feed changeFeed
+	events, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+
+	// Notifying never blocks, even with nobody reading
+	for range changeEventBuffer + 10 {
+		feed.notify(ChangeEvent{Type: ChangeUpdated})
+	}
+	if len(events) != changeEventBuffer {
+		t.Errorf("Expected %d buffered events, got %d", changeEventBuffer, len(events))
+	}
+}
@@ -0,0 +1,40 @@
+package dfdb_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfdb/dfdbtest"
+)
+
+func TestFsProviderConformance(t *testing.T) {
+	dfdbtest.TestProvider(t, func(t *testing.T, dir string) dfdb.Provider {
+		provider, err := dfdb.NewFsProvider(dir)
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+		return provider
+	})
+}
+
+func TestSqliteProviderConformance(t *testing.T) {
+	dfdbtest.TestProvider(t, func(t *testing.T, dir string) dfdb.Provider {
+		provider, err := dfdb.NewSqliteProvider(filepath.Join(dir, "characters.db"))
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+		t.Cleanup(func() { provider.Close() })
+		return provider
+	})
+}
+
+func TestMemoryProviderConformance(t *testing.T) {
+	// Nothing is stored, opening the same dir again returns the same provider
+	var providers sync.Map
+	dfdbtest.TestProvider(t, func(t *testing.T, dir string) dfdb.Provider {
+		provider, _ := providers.LoadOrStore(dir, dfdb.NewMemoryProvider(nil))
+		return provider.(*dfdb.MemoryProvider)
+	})
+}
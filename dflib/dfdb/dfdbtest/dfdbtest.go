@@ -0,0 +1,647 @@
+// Package dfdbtest implements support for testing implementations of dfdb.Provider.
+package dfdbtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// eventTimeout is how long to wait for a change event
+const eventTimeout = time.Second
+
+// OpenProvider opens a provider on the storage in dir, failing the test if it cannot.
+// Opening the same dir again must open the same stored characters. Providers that
+// store nothing can return the provider already opened for dir.
+type OpenProvider func(t *testing.T, dir string) dfdb.Provider
+
+// TestProvider checks that a provider implements the dfdb.Provider contract:
+// storing and reading characters, duplicate IDs, empty lists, revisions and stale updates,
+// not-found errors, name validation, List queries, change events and concurrent use.
+// Each check runs as a subtest on a provider opened on a new directory.
+func TestProvider(t *testing.T, open OpenProvider) {
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			check.test(t, open)
+		})
+	}
+}
+
+// checks are the parts of the provider contract checked by TestProvider
+var checks = []struct {
+	name string
+	test func(t *testing.T, open OpenProvider)
+}{
+	{"CreateAndRead", testCreateAndRead},
+	{"CreateKeepsAllFields", testCreateKeepsAllFields},
+	{"CreateExisting", testCreateExisting},
+	{"EmptyLists", testEmptyLists},
+	{"ReadNotFound", testReadNotFound},
+	{"Update", testUpdate},
+	{"UpdateNotFound", testUpdateNotFound},
+	{"UpdateRejectsStaleRevision", testUpdateRejectsStaleRevision},
+	{"Delete", testDelete},
+	{"DeleteNotFound", testDeleteNotFound},
+	{"InvalidCharacterName", testInvalidCharacterName},
+	{"ListAll", testListAll},
+	{"ListFilters", testListFilters},
+	{"ListQuery", testListQuery},
+	{"ListSortAndPages", testListSortAndPages},
+	{"Persistence", testPersistence},
+	{"Subscribe", testSubscribe},
+	{"ConcurrentAccess", testConcurrentAccess},
+}
+
+// testCharacter returns a character with the given ID and name
+func testCharacter(id, name string) dfm.Character {
+	return dfm.Character{
+		ID:       id,
+		Name:     name,
+		Spirit:   string(dfm.SpiritHuman),
+		Group:    string(dfm.PC),
+		Player:   "testuser",
+		Category: "character",
+	}
+}
+
+// create stores characters, failing the test if one cannot be stored
+func create(t *testing.T, provider dfdb.Provider, characters ...dfm.Character) {
+	t.Helper()
+	for _, character := range characters {
+		if err := provider.Create(character); err != nil {
+			t.Fatalf("Failed to create %s: %v", character.Name, err)
+		}
+	}
+}
+
+// listIDs lists the sorted IDs of the characters matching a query
+func listIDs(t *testing.T, provider dfdb.Provider, query dfm.CharacterQuery) []string {
+	t.Helper()
+	characters, err := provider.List(query)
+	if err != nil {
+		t.Fatalf("Failed to list %+v: %v", query, err)
+	}
+	ids := []string{}
+	for _, character := range characters {
+		ids = append(ids, character.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func testCreateAndRead(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Test Character")
+	create(t, provider, char)
+
+	read, err := provider.Read(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if read.Name != char.Name || read.Spirit != char.Spirit || read.Player != char.Player {
+		t.Errorf("Read %+v, want %+v", read, char)
+	}
+	if read.Revision != 0 {
+		t.Errorf("New character should be at revision 0, got %d", read.Revision)
+	}
+}
+
+func testCreateKeepsAllFields(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	char := dfm.NewCharacter(dfm.SpiritVampire)
+	char.Name = "Lucius Aurelius"
+	char.Player = "gm"
+	char.Aliases = []string{"The Red", "Old Man"}
+	char.Tags = []string{"elder"}
+	char.Collectives = []string{"Camarilla"}
+	char.EmbraceYear = -44
+	char.Description = "A Roman noble"
+	char.Aspects[0] = dfm.Aspect{Type: "high concept", Title: "Ancient Roman Noble", Description: "Remembers the Republic"}
+	char.Skills[2].Rating = 4
+	char.Stunts = []dfm.Stunt{{Title: "Commanding", Description: "+2 to provoke"}}
+	char.Disciplines[0].Rating = 3
+	char.Consequences[0] = dfm.Consequence{Level: 2, IsActive: true, Title: "Singed"}
+	char.FatePoint = 2
+	char.HungerStressCurrent = 1
+	char.Revision = 7 // Restored characters keep their revision
+	create(t, provider, char)
+
+	read, err := provider.Read(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if changes := dfm.Diff(char, read); len(changes) != 0 {
+		t.Errorf("Character changed when stored: %+v", changes)
+	}
+	if read.Revision != char.Revision {
+		t.Errorf("Revision not kept: got %d, want %d", read.Revision, char.Revision)
+	}
+}
+
+func testCreateExisting(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Original Character")
+	create(t, provider, char)
+
+	duplicate := testCharacter(char.ID, "Duplicate Character")
+	if err := provider.Create(duplicate); !errors.Is(err, dfdb.ErrCharacterExists) {
+		t.Errorf("Expected ErrCharacterExists, got %v", err)
+	}
+
+	read, err := provider.Read(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if read.Name != char.Name {
+		t.Errorf("Stored character was replaced by %s", read.Name)
+	}
+	if ids := listIDs(t, provider, dfm.CharacterQuery{}); len(ids) != 1 {
+		t.Errorf("Expected 1 character, got %v", ids)
+	}
+}
+
+// checkEmptyLists fails the test if a list of the character is nil or not empty
+func checkEmptyLists(t *testing.T, c dfm.Character) {
+	t.Helper()
+	lists := map[string]int{
+		"aliases": len(c.Aliases), "tags": len(c.Tags), "collectives": len(c.Collectives),
+		"aspects": len(c.Aspects), "skills": len(c.Skills), "stunts": len(c.Stunts),
+		"disciplines": len(c.Disciplines), "consequences": len(c.Consequences),
+	}
+	nils := map[string]bool{
+		"aliases": c.Aliases == nil, "tags": c.Tags == nil, "collectives": c.Collectives == nil,
+		"aspects": c.Aspects == nil, "skills": c.Skills == nil, "stunts": c.Stunts == nil,
+		"disciplines": c.Disciplines == nil, "consequences": c.Consequences == nil,
+	}
+	for name, length := range lists {
+		if nils[name] || length != 0 {
+			t.Errorf("%s: %s should be an empty list, got nil %v and length %d", c.Name, name, nils[name], length)
+		}
+	}
+}
+
+func testEmptyLists(t *testing.T, open OpenProvider) {
+	dir := t.TempDir()
+	provider := open(t, dir)
+
+	// Nil and empty lists are both stored and read as empty lists
+	withNil := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Nil Lists")
+	withEmpty := testCharacter("550e8400-e29b-41d4-a716-446655440001", "Empty Lists")
+	withEmpty.Aliases, withEmpty.Tags, withEmpty.Collectives = []string{}, []string{}, []string{}
+	withEmpty.Aspects, withEmpty.Skills, withEmpty.Stunts = []dfm.Aspect{}, []dfm.Skill{}, []dfm.Stunt{}
+	withEmpty.Disciplines, withEmpty.Consequences = []dfm.Discipline{}, []dfm.Consequence{}
+	create(t, provider, withNil, withEmpty)
+
+	updated := testCharacter(withNil.ID, withNil.Name)
+	if err := provider.Update(updated, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+
+	for _, p := range []dfdb.Provider{provider, open(t, dir)} {
+		characters, err := p.List(dfm.CharacterQuery{})
+		if err != nil {
+			t.Fatalf("Failed to list characters: %v", err)
+		}
+		if len(characters) != 2 {
+			t.Fatalf("Expected 2 characters, got %d", len(characters))
+		}
+		for _, character := range characters {
+			checkEmptyLists(t, character)
+			read, err := p.Read(character.ID)
+			if err != nil {
+				t.Fatalf("Failed to read character: %v", err)
+			}
+			checkEmptyLists(t, read)
+		}
+	}
+}
+
+func testReadNotFound(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	if _, err := provider.Read("non-existent-id"); !errors.Is(err, dfdb.ErrCharacterNotFound) {
+		t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+	}
+}
+
+func testUpdate(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Original Name")
+	create(t, provider, char)
+
+	char.Name = "Updated Name"
+	char.Tags = []string{"renamed"}
+	if err := provider.Update(char, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+
+	read, _ := provider.Read(char.ID)
+	if read.Name != "Updated Name" || len(read.Tags) != 1 {
+		t.Errorf("Character not updated: %+v", read)
+	}
+	if read.Revision != 1 {
+		t.Errorf("Revision not incremented: got %d, want 1", read.Revision)
+	}
+	if read.Modified.IsZero() {
+		t.Error("Modification time not set")
+	}
+
+	// Lists can get shorter
+	char.Tags = nil
+	if err := provider.Update(char, 1); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+	if read, _ := provider.Read(char.ID); len(read.Tags) != 0 || read.Revision != 2 {
+		t.Errorf("Expected no tags at revision 2, got %v at %d", read.Tags, read.Revision)
+	}
+}
+
+func testUpdateNotFound(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	err := provider.Update(testCharacter("non-existent-id", "Nobody"), 0)
+	if !errors.Is(err, dfdb.ErrCharacterNotFound) {
+		t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+	}
+	if _, err := provider.Read("non-existent-id"); !errors.Is(err, dfdb.ErrCharacterNotFound) {
+		t.Errorf("Update should not create the character, got %v", err)
+	}
+}
+
+func testUpdateRejectsStaleRevision(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Original Name")
+	create(t, provider, char)
+
+	// Two users read revision 0, the first one saves
+	first, second := char, char
+	first.FatePoint = 1
+	second.FatePoint = 2
+	if err := provider.Update(first, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+
+	// The second one is based on an old revision
+	err := provider.Update(second, 0)
+	var stale *dfdb.StaleRevisionError
+	if !errors.As(err, &stale) || !errors.Is(err, dfdb.ErrStaleRevision) {
+		t.Fatalf("Expected a stale revision error, got %v", err)
+	}
+	if stale.ExpectedRevision != 0 || stale.Current.Revision != 1 || stale.Current.FatePoint != 1 {
+		t.Errorf("Stale revision error should hold the current character: %+v", stale)
+	}
+	if read, _ := provider.Read(char.ID); read.FatePoint != 1 {
+		t.Errorf("Stale update should not be stored, got fate point %d", read.FatePoint)
+	}
+
+	// Overwriting with the current revision succeeds
+	if err := provider.Update(second, stale.Current.Revision); err != nil {
+		t.Fatalf("Failed to overwrite character: %v", err)
+	}
+	read, _ := provider.Read(char.ID)
+	if read.FatePoint != 2 || read.Revision != 2 {
+		t.Errorf("Expected fate point 2 at revision 2, got %d at %d", read.FatePoint, read.Revision)
+	}
+}
+
+func testDelete(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "To Delete")
+	other := testCharacter("550e8400-e29b-41d4-a716-446655440001", "To Keep")
+	create(t, provider, char, other)
+
+	if err := provider.Delete(char.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+
+	if _, err := provider.Read(char.ID); !errors.Is(err, dfdb.ErrCharacterNotFound) {
+		t.Errorf("Expected ErrCharacterNotFound after deletion, got %v", err)
+	}
+	if ids := listIDs(t, provider, dfm.CharacterQuery{}); len(ids) != 1 || ids[0] != other.ID {
+		t.Errorf("Expected only %s to be left, got %v", other.ID, ids)
+	}
+}
+
+func testDeleteNotFound(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	if err := provider.Delete("non-existent-id"); !errors.Is(err, dfdb.ErrCharacterNotFound) {
+		t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+	}
+}
+
+func testInvalidCharacterName(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	tests := []struct {
+		name     string
+		charName string
+		wantErr  bool
+	}{
+		{"Valid name", "John Smith", false},
+		{"Valid with numbers", "John Smith 3rd", false},
+		{"Invalid with dash", "John-Smith", true},
+		{"Invalid with special char", "John@Smith", true},
+		{"Invalid with slash", "John/Smith", true},
+		{"Empty name allowed", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			char := testCharacter("550e8400-e29b-41d4-a716-446655440000", tt.charName)
+
+			err := provider.Create(char)
+			if errors.Is(err, dfdb.ErrInvalidCharacterName) != tt.wantErr {
+				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Renaming a stored character is validated too
+			valid := testCharacter("550e8400-e29b-41d4-a716-446655440001", "Valid Name")
+			create(t, provider, valid)
+			valid.Name = tt.charName
+			err = provider.Update(valid, 0)
+			if errors.Is(err, dfdb.ErrInvalidCharacterName) != tt.wantErr {
+				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Clean up for next test
+			provider.Delete(char.ID)
+			provider.Delete(valid.ID)
+		})
+	}
+}
+
+func testListAll(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+
+	if ids := listIDs(t, provider, dfm.CharacterQuery{}); len(ids) != 0 {
+		t.Errorf("Expected no characters, got %v", ids)
+	}
+
+	create(t, provider,
+		dfm.Character{ID: "id-1", Name: "Char One", Spirit: "human", Group: "pc"},
+		dfm.Character{ID: "id-2", Name: "Char Two", Spirit: "vampire", Group: "npc"},
+		dfm.Character{ID: "id-3", Name: "Char Three", Spirit: "ghoul", Group: "pc"},
+	)
+
+	if ids := listIDs(t, provider, dfm.CharacterQuery{}); fmt.Sprint(ids) != "[id-1 id-2 id-3]" {
+		t.Errorf("Expected all 3 characters, got %v", ids)
+	}
+}
+
+func testListFilters(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	create(t, provider,
+		dfm.Character{ID: "id-1", Name: "Char One", Spirit: "vampire", Group: "pc", Player: "alice"},
+		dfm.Character{ID: "id-2", Name: "Char Two", Spirit: "vampire", Group: "npc", Player: "alice"},
+		dfm.Character{ID: "id-3", Name: "Char Three", Spirit: "human", Group: "pc", Player: "alice"},
+		dfm.Character{ID: "id-4", Name: "Char Four", Spirit: "vampire", Group: "pc", Player: "bob"},
+	)
+
+	tests := []struct {
+		name  string
+		query dfm.CharacterQuery
+		want  string
+	}{
+		{"Spirit", dfm.CharacterQuery{Spirit: "vampire"}, "[id-1 id-2 id-4]"},
+		{"Group", dfm.CharacterQuery{Group: "npc"}, "[id-2]"},
+		{"Player", dfm.CharacterQuery{Player: "alice"}, "[id-1 id-2 id-3]"},
+		{"All filters", dfm.CharacterQuery{Spirit: "vampire", Group: "pc", Player: "alice"}, "[id-1]"},
+		{"No match", dfm.CharacterQuery{Spirit: "ghoul"}, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ids := listIDs(t, provider, tt.query); fmt.Sprint(ids) != tt.want {
+				t.Errorf("List(%+v) = %v, want %s", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func testListQuery(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	create(t, provider,
+		dfm.Character{ID: "id-1", Name: "Lucius Aurelius", Aliases: []string{"The Red"}, Spirit: "vampire", Group: "npc",
+			Tags: []string{"Elder", "roman"}, Collectives: []string{"Camarilla"},
+			Aspects: []dfm.Aspect{{Title: "Ancient Roman Noble", Description: "Remembers the Republic"}}},
+		dfm.Character{ID: "id-2", Name: "Maria Santos", Spirit: "ghoul", Group: "pc",
+			Tags: []string{"roman"}, Collectives: []string{"Camarilla", "Church"}},
+		dfm.Character{ID: "id-3", Name: "John Smith", Aliases: []string{"Johnny"}, Spirit: "human", Group: "pc",
+			Collectives: []string{"Church"}},
+	)
+
+	tests := []struct {
+		name  string
+		query dfm.CharacterQuery
+		want  string
+	}{
+		{"Tag ignoring case", dfm.CharacterQuery{Tags: []string{"elder"}}, "[id-1]"},
+		{"All tags", dfm.CharacterQuery{Tags: []string{"roman", "elder"}}, "[id-1]"},
+		{"Any tag", dfm.CharacterQuery{Tags: []string{"roman", "elder"}, Match: dfm.MatchAny}, "[id-1 id-2]"},
+		{"Collective", dfm.CharacterQuery{Collectives: []string{"church"}}, "[id-2 id-3]"},
+		{"Aspect title", dfm.CharacterQuery{Aspect: "roman noble"}, "[id-1]"},
+		{"Aspect description", dfm.CharacterQuery{Aspect: "republic"}, "[id-1]"},
+		{"Name", dfm.CharacterQuery{Name: "santos"}, "[id-2]"},
+		{"Alias", dfm.CharacterQuery{Name: "johnny"}, "[id-3]"},
+		{"Fuzzy name", dfm.CharacterQuery{Name: "lcs", Fuzzy: true}, "[id-1]"},
+		{"Fuzzy alias", dfm.CharacterQuery{Name: "thrd", Fuzzy: true}, "[id-1]"},
+		{"Not fuzzy", dfm.CharacterQuery{Name: "lcs"}, "[]"},
+		{"All filters", dfm.CharacterQuery{Spirit: "ghoul", Collectives: []string{"Church"}}, "[id-2]"},
+		{"Any filter", dfm.CharacterQuery{Spirit: "ghoul", Collectives: []string{"Church"}, Match: dfm.MatchAny}, "[id-2 id-3]"},
+		{"Any filter with group", dfm.CharacterQuery{Group: "npc", Name: "john", Match: dfm.MatchAny}, "[id-1 id-3]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ids := listIDs(t, provider, tt.query); fmt.Sprint(ids) != tt.want {
+				t.Errorf("List(%+v) = %v, want %s", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func testListSortAndPages(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	create(t, provider,
+		dfm.Character{ID: "id-1", Name: "charlie", EmbraceYear: 1890},
+		dfm.Character{ID: "id-2", Name: "Alice", EmbraceYear: 1990},
+		dfm.Character{ID: "id-3", Name: "Bob", EmbraceYear: 1890},
+	)
+	// Alice changes last
+	time.Sleep(10 * time.Millisecond)
+	alice, _ := provider.Read("id-2")
+	alice.FatePoint = 1
+	if err := provider.Update(alice, alice.Revision); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query dfm.CharacterQuery
+		want  string
+	}{
+		{"Name ignoring case", dfm.CharacterQuery{}, "[id-2 id-3 id-1]"},
+		{"Name descending", dfm.CharacterQuery{Descending: true}, "[id-1 id-3 id-2]"},
+		{"Embrace year, then name", dfm.CharacterQuery{Sort: dfm.SortByEmbraceYear}, "[id-3 id-1 id-2]"},
+		{"Modified", dfm.CharacterQuery{Sort: dfm.SortByModified, Offset: 2}, "[id-2]"},
+		{"Offset", dfm.CharacterQuery{Offset: 1}, "[id-3 id-1]"},
+		{"Limit", dfm.CharacterQuery{Limit: 2}, "[id-2 id-3]"},
+		{"Page", dfm.CharacterQuery{Offset: 1, Limit: 1}, "[id-3]"},
+		{"Offset past the end", dfm.CharacterQuery{Offset: 5}, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			characters, err := provider.List(tt.query)
+			if err != nil {
+				t.Fatalf("Failed to list %+v: %v", tt.query, err)
+			}
+			ids := []string{}
+			for _, character := range characters {
+				ids = append(ids, character.ID)
+			}
+			if fmt.Sprint(ids) != tt.want {
+				t.Errorf("List(%+v) = %v, want %s", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func testPersistence(t *testing.T, open OpenProvider) {
+	dir := t.TempDir()
+	provider := open(t, dir)
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Persistent Character")
+	create(t, provider, char)
+	char.FatePoint = 3
+	if err := provider.Update(char, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+
+	// Open the same storage again
+	reopened := open(t, dir)
+	read, err := reopened.Read(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character from reopened provider: %v", err)
+	}
+	if read.Name != char.Name || read.FatePoint != 3 || read.Revision != 1 {
+		t.Errorf("Expected %s with fate point 3 at revision 1, got %+v", char.Name, read)
+	}
+}
+
+// receive waits for a change event, failing the test if none arrives
+func receive(t *testing.T, events <-chan dfdb.ChangeEvent) dfdb.ChangeEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(eventTimeout):
+		t.Fatal("Timed out waiting for a change event")
+		return dfdb.ChangeEvent{}
+	}
+}
+
+func testSubscribe(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	events, unsubscribe := provider.Subscribe()
+	defer unsubscribe()
+
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Watched")
+	create(t, provider, char)
+	if event := receive(t, events); event.Type != dfdb.ChangeCreated || event.CharacterID != char.ID || event.Character.Name != char.Name {
+		t.Errorf("Unexpected create event %+v", event)
+	}
+
+	char.Name = "Renamed"
+	if err := provider.Update(char, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+	if event := receive(t, events); event.Type != dfdb.ChangeUpdated || event.Character.Name != "Renamed" || event.Character.Revision != 1 {
+		t.Errorf("Unexpected update event %+v", event)
+	}
+
+	// Failed changes send no events
+	provider.Update(char, 0)
+	provider.Delete("non-existent-id")
+
+	if err := provider.Delete(char.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+	if event := receive(t, events); event.Type != dfdb.ChangeDeleted || event.CharacterID != char.ID || event.Character.Name != "Renamed" {
+		t.Errorf("Unexpected delete event %+v", event)
+	}
+
+	// Ending the subscription closes the channel
+	unsubscribe()
+	for range events {
+	}
+}
+
+func testConcurrentAccess(t *testing.T, open OpenProvider) {
+	provider := open(t, t.TempDir())
+	char := testCharacter("550e8400-e29b-41d4-a716-446655440000", "Concurrent Test")
+	create(t, provider, char)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	updated := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+
+		// Readers
+		go func() {
+			defer wg.Done()
+			provider.Read(char.ID)
+		}()
+		go func() {
+			defer wg.Done()
+			provider.List(dfm.CharacterQuery{})
+		}()
+
+		// Writers: creates of new characters and updates of a shared one
+		go func() {
+			defer wg.Done()
+			other := testCharacter(fmt.Sprintf("550e8400-e29b-41d4-a716-4466554401%02d", i), fmt.Sprintf("Other %d", i))
+			if err := provider.Create(other); err != nil {
+				t.Errorf("Failed to create character: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Retry until the update is based on the current revision
+			for {
+				current, err := provider.Read(char.ID)
+				if err != nil {
+					t.Errorf("Failed to read character: %v", err)
+					return
+				}
+				current.FatePoint++
+				err = provider.Update(current, current.Revision)
+				if err == nil {
+					mu.Lock()
+					updated++
+					mu.Unlock()
+					return
+				}
+				if !errors.Is(err, dfdb.ErrStaleRevision) {
+					t.Errorf("Failed to update character: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// No update was lost
+	read, _ := provider.Read(char.ID)
+	if read.FatePoint != updated || read.Revision != updated {
+		t.Errorf("Expected %d updates, got fate point %d at revision %d", updated, read.FatePoint, read.Revision)
+	}
+	if ids := listIDs(t, provider, dfm.CharacterQuery{}); len(ids) != 11 {
+		t.Errorf("Expected 11 characters, got %d", len(ids))
+	}
+}
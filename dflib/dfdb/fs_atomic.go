@@ -0,0 +1,78 @@
+package dfdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeTempFile writes data to a temporary file. It is replaced in tests to simulate interrupted writes.
+var writeTempFile = func(file *os.File, data []byte) error {
+	_, err := file.Write(data)
+	return err
+}
+
+// writeFileAtomic replaces the file at path with data so that the file holds either
+// the old or the new data even if writing is interrupted by a crash or a full disk.
+// The data is written to a temporary file in the same directory, synced to disk and
+// renamed over path.
+//
+// If oldPath is not empty and differs from path, the file at oldPath is moved to path
+// before the new data replaces it, so that at no point two files exist for the same document.
+func writeFileAtomic(path string, data []byte, oldPath string) error {
+	dir := filepath.Dir(path)
+
+	// Temporary files end in .tmp so they are never loaded as documents
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath) // Fails harmlessly once renamed
+
+	err = writeTempFile(temp, data)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	moved := false
+	if oldPath != "" && oldPath != path {
+		err := os.Rename(oldPath, path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rename file %s: %w", oldPath, err)
+		}
+		moved = err == nil
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		if moved {
+			// Put the old file back where the caller expects it
+			os.Rename(path, oldPath)
+		}
+		return fmt.Errorf("failed to replace file %s: %w", path, err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes the renames in a directory to disk. Not every platform can sync
+// a directory, so errors are ignored; the renames are still atomic without it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
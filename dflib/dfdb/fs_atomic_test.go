@@ -0,0 +1,196 @@
+package dfdb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// interruptWrites makes writes stop halfway with a full disk until the test ends
+func interruptWrites(t *testing.T) {
+	t.Helper()
+	original := writeTempFile
+	writeTempFile = func(file *os.File, data []byte) error {
+		file.Write(data[:len(data)/2])
+		return syscall.ENOSPC
+	}
+	t.Cleanup(func() { writeTempFile = original })
+}
+
+// dirFilenames returns the names of the files in a directory
+func dirFilenames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.json")
+
+	if err := writeFileAtomic(path, []byte("first"), ""); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := writeFileAtomic(path, []byte("second"), ""); err != nil {
+		t.Fatalf("Failed to replace file: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "second" {
+		t.Errorf("File content = %q, want second", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("File mode = %v, want 0644", info.Mode().Perm())
+	}
+	if names := dirFilenames(t, dir); len(names) != 1 {
+		t.Errorf("Directory should only contain the file, got %v", names)
+	}
+}
+
+func TestWriteFileAtomicMovesOldFile(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	path := filepath.Join(dir, "new.json")
+	os.WriteFile(oldPath, []byte("old"), 0644)
+
+	if err := writeFileAtomic(path, []byte("new"), oldPath); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("Old file should have been moved")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("File content = %q, want new", data)
+	}
+
+	// A missing old file is not an error
+	if err := writeFileAtomic(filepath.Join(dir, "other.json"), []byte("other"), oldPath); err != nil {
+		t.Errorf("Missing old file should be ignored: %v", err)
+	}
+}
+
+func TestWriteFileAtomicInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.json")
+	os.WriteFile(path, []byte("original"), 0644)
+	interruptWrites(t)
+
+	err := writeFileAtomic(path, []byte("replacement"), "")
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("Expected a full disk error, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "original" {
+		t.Errorf("File content = %q, want original", data)
+	}
+	if names := dirFilenames(t, dir); len(names) != 1 {
+		t.Errorf("Temporary file should have been removed, got %v", names)
+	}
+}
+
+func TestUpdateInterruptedKeepsCharacter(t *testing.T) {
+	dir := t.TempDir()
+	provider, _ := NewFsProvider(dir)
+
+	char := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Old Name", Spirit: "human"}
+	provider.Create(char)
+
+	interruptWrites(t)
+	for _, name := range []string{"Old Name", "New Name"} {
+		updated := char
+		updated.Name = name
+		updated.FatePoint = 5
+		if err := provider.Update(updated, 0); err == nil {
+			t.Fatalf("Update to %q should fail", name)
+		}
+	}
+
+	// The cache and the files still hold the character as created
+	if read, _ := provider.Read(char.ID); read.FatePoint != 0 || read.Name != "Old Name" {
+		t.Errorf("Cached character changed: %+v", read)
+	}
+	if names := dirFilenames(t, dir); len(names) != 1 || names[0] != "old_name_550e8400-e29b-41d4-a716-446655440000.json" {
+		t.Errorf("Directory should only contain the old file, got %v", names)
+	}
+	reloaded, _ := NewFsProvider(dir)
+	if read, err := reloaded.Read(char.ID); err != nil || read.Name != "Old Name" {
+		t.Errorf("Character should survive the interrupted update: %+v, %v", read, err)
+	}
+}
+
+func TestCreateInterruptedLeavesNoFile(t *testing.T) {
+	dir := t.TempDir()
+	provider, _ := NewFsProvider(dir)
+	interruptWrites(t)
+
+	char := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Test Character"}
+	if err := provider.Create(char); err == nil {
+		t.Fatal("Create should fail")
+	}
+	if _, err := provider.Read(char.ID); err != ErrCharacterNotFound {
+		t.Errorf("Character should not be cached, got %v", err)
+	}
+	if names := dirFilenames(t, dir); len(names) != 0 {
+		t.Errorf("Directory should be empty, got %v", names)
+	}
+}
+
+func TestLoadCacheIgnoresTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	provider, _ := NewFsProvider(dir)
+	char := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Test Character"}
+	provider.Create(char)
+
+	// A crash while writing leaves a partial temporary file behind
+	filename := "test_character_550e8400-e29b-41d4-a716-446655440000.json"
+	os.WriteFile(filepath.Join(dir, "."+filename+".123.tmp"), []byte(`{"id": "550e8400-e29b-41d4`), 0600)
+
+	reloaded, err := NewFsProvider(dir)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if read, err := reloaded.Read(char.ID); err != nil || read.Name != "Test Character" {
+		t.Errorf("Character should load from the complete file: %+v, %v", read, err)
+	}
+}
+
+func TestStoreSaveInterruptedKeepsDocument(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+	interruptWrites(t)
+
+	chronicle, _ := provider.ReadChronicle(testChronicleID)
+	chronicle.Name = "Tampere by Night"
+	if err := provider.UpdateChronicle(chronicle); err == nil {
+		t.Fatal("UpdateChronicle should fail")
+	}
+
+	if read, _ := provider.ReadChronicle(testChronicleID); read.Name != "Helsinki by Night" {
+		t.Errorf("Cached chronicle changed: %+v", read)
+	}
+	var files []string
+	filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && strings.Contains(path, testChronicleID) {
+			files = append(files, entry.Name())
+		}
+		return nil
+	})
+	if len(files) != 1 || !strings.HasPrefix(files[0], "helsinki_by_night_") {
+		t.Errorf("Only the old chronicle file should exist, got %v", files)
+	}
+}
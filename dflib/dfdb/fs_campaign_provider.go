@@ -0,0 +1,574 @@
+package dfdb
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+const (
+	// ChroniclesDir is the subdirectory for chronicle files
+	ChroniclesDir = "chronicles"
+	// CampaignsDir is the subdirectory for campaign files
+	CampaignsDir = "campaigns"
+	// SessionsDir is the subdirectory for session files
+	SessionsDir = "sessions"
+	// ReadmeFile is the name of the Markdown README stored in a chronicle's or campaign's folder
+	ReadmeFile = "README.md"
+	// NotesFile is the name of the Markdown notes stored in a session's folder
+	NotesFile = "NOTES.md"
+	// ResourcesDir is the folder of downloadable files in a chronicle's or campaign's folder
+	ResourcesDir = "resources"
+)
+
+// ErrChronicleNotFound is returned when a chronicle cannot be found
+var ErrChronicleNotFound = errors.New("chronicle not found")
+
+// ErrCampaignNotFound is returned when a campaign cannot be found
+var ErrCampaignNotFound = errors.New("campaign not found")
+
+// ErrSessionNotFound is returned when a session cannot be found
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrInvalidName is returned when a chronicle, campaign or session name contains invalid characters
+var ErrInvalidName = errors.New("name contains invalid characters: only alphanumeric characters and spaces are allowed")
+
+// ErrDocumentNotFound is returned when a README or notes document cannot be found
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrResourceNotFound is returned when a resource file cannot be found
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrHasChildren is returned when deleting a chronicle or campaign that still has campaigns or sessions
+var ErrHasChildren = errors.New("cannot delete: still has campaigns or sessions")
+
+// FsCampaignProvider implements the CampaignProvider interface using filesystem JSON storage.
+// Chronicles, campaigns and sessions are stored in their own subdirectories of the
+// database directory, one {name}_{uuid}.json file each. Documents belonging to a
+// chronicle, campaign or session, such as a README.md or NOTES.md, are stored next
+// to it in a folder named by its ID.
+type FsCampaignProvider struct {
+	mu         sync.RWMutex
+	chronicles *fsStore[dfm.Chronicle]
+	campaigns  *fsStore[dfm.Campaign]
+	sessions   *fsStore[dfm.Session]
+	now        func() time.Time // clock used for timestamps
+}
+
+// NewFsCampaignProvider creates a new filesystem-based chronicle, campaign and session provider.
+// The subdirectories are created under dir if they do not exist.
+func NewFsCampaignProvider(dir string) (*FsCampaignProvider, error) {
+	chronicles, err := newFsStore(filepath.Join(dir, ChroniclesDir),
+		func(c dfm.Chronicle) string { return c.ID },
+		func(c dfm.Chronicle) string { return c.Name })
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chronicles: %w", err)
+	}
+
+	campaigns, err := newFsStore(filepath.Join(dir, CampaignsDir),
+		func(c dfm.Campaign) string { return c.ID },
+		func(c dfm.Campaign) string { return c.Name })
+	if err != nil {
+		return nil, fmt.Errorf("failed to open campaigns: %w", err)
+	}
+
+	sessions, err := newFsStore(filepath.Join(dir, SessionsDir),
+		func(s dfm.Session) string { return s.ID },
+		func(s dfm.Session) string { return s.Name })
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sessions: %w", err)
+	}
+
+	return &FsCampaignProvider{
+		chronicles: chronicles,
+		campaigns:  campaigns,
+		sessions:   sessions,
+		now:        time.Now,
+	}, nil
+}
+
+// CreateChronicle stores a new chronicle.
+func (f *FsCampaignProvider) CreateChronicle(chronicle dfm.Chronicle) error {
+	if err := validateName(chronicle.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	chronicle.CreatedAt, chronicle.UpdatedAt = f.timestamps(chronicle.CreatedAt)
+	return f.chronicles.save(chronicle)
+}
+
+// ReadChronicle retrieves a chronicle by ID from the cache.
+func (f *FsCampaignProvider) ReadChronicle(chronicleID string) (dfm.Chronicle, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if chronicle, ok := f.chronicles.read(chronicleID); ok {
+		return chronicle, nil
+	}
+	return dfm.Chronicle{}, ErrChronicleNotFound
+}
+
+// UpdateChronicle modifies an existing chronicle.
+// If the chronicle name has changed, the file will be renamed.
+func (f *FsCampaignProvider) UpdateChronicle(chronicle dfm.Chronicle) error {
+	if err := validateName(chronicle.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing, ok := f.chronicles.read(chronicle.ID)
+	if !ok {
+		return ErrChronicleNotFound
+	}
+
+	chronicle.CreatedAt, chronicle.UpdatedAt = f.timestamps(existing.CreatedAt)
+	return f.chronicles.save(chronicle)
+}
+
+// DeleteChronicle removes a chronicle by ID.
+// A chronicle that still has campaigns cannot be deleted.
+func (f *FsCampaignProvider) DeleteChronicle(chronicleID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.chronicles.read(chronicleID); !ok {
+		return ErrChronicleNotFound
+	}
+
+	children := f.campaigns.list(func(c dfm.Campaign) bool { return c.ChronicleID == chronicleID })
+	if len(children) > 0 {
+		return ErrHasChildren
+	}
+
+	_, err := f.chronicles.remove(chronicleID)
+	return err
+}
+
+// ListChronicles returns chronicles matching the query filters, sorted by name.
+func (f *FsCampaignProvider) ListChronicles(query dfm.ChronicleQuery) ([]dfm.Chronicle, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := f.chronicles.list(func(c dfm.Chronicle) bool {
+		return matchesChronicleQuery(c, query)
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// ReadChronicleReadme returns the Markdown README of a chronicle.
+// The README is read from chronicles/{uuid}/README.md.
+func (f *FsCampaignProvider) ReadChronicleReadme(chronicleID string) (string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.chronicles.read(chronicleID); !ok {
+		return "", ErrChronicleNotFound
+	}
+	return readDocument(filepath.Join(f.chronicles.dir, chronicleID, ReadmeFile))
+}
+
+// ListChronicleResources returns the downloadable files of a chronicle.
+// Resources are stored in chronicles/{uuid}/resources.
+func (f *FsCampaignProvider) ListChronicleResources(chronicleID string) ([]dfm.Resource, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.chronicles.read(chronicleID); !ok {
+		return nil, ErrChronicleNotFound
+	}
+	return listResources(filepath.Join(f.chronicles.dir, chronicleID, ResourcesDir))
+}
+
+// OpenChronicleResource opens a downloadable file of a chronicle for reading.
+func (f *FsCampaignProvider) OpenChronicleResource(chronicleID, name string) (fs.File, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.chronicles.read(chronicleID); !ok {
+		return nil, ErrChronicleNotFound
+	}
+	return openResource(filepath.Join(f.chronicles.dir, chronicleID, ResourcesDir), name)
+}
+
+// CreateCampaign stores a new campaign.
+// The parent chronicle must exist if ChronicleID is set.
+func (f *FsCampaignProvider) CreateCampaign(campaign dfm.Campaign) error {
+	if err := validateName(campaign.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkChronicle(campaign.ChronicleID); err != nil {
+		return err
+	}
+
+	campaign.CreatedAt, campaign.UpdatedAt = f.timestamps(campaign.CreatedAt)
+	return f.campaigns.save(campaign)
+}
+
+// ReadCampaign retrieves a campaign by ID from the cache.
+func (f *FsCampaignProvider) ReadCampaign(campaignID string) (dfm.Campaign, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if campaign, ok := f.campaigns.read(campaignID); ok {
+		return campaign, nil
+	}
+	return dfm.Campaign{}, ErrCampaignNotFound
+}
+
+// UpdateCampaign modifies an existing campaign.
+// If the campaign name has changed, the file will be renamed.
+func (f *FsCampaignProvider) UpdateCampaign(campaign dfm.Campaign) error {
+	if err := validateName(campaign.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing, ok := f.campaigns.read(campaign.ID)
+	if !ok {
+		return ErrCampaignNotFound
+	}
+	if err := f.checkChronicle(campaign.ChronicleID); err != nil {
+		return err
+	}
+
+	campaign.CreatedAt, campaign.UpdatedAt = f.timestamps(existing.CreatedAt)
+	return f.campaigns.save(campaign)
+}
+
+// DeleteCampaign removes a campaign by ID.
+// A campaign that still has sessions cannot be deleted.
+func (f *FsCampaignProvider) DeleteCampaign(campaignID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.campaigns.read(campaignID); !ok {
+		return ErrCampaignNotFound
+	}
+
+	children := f.sessions.list(func(s dfm.Session) bool { return s.CampaignID == campaignID })
+	if len(children) > 0 {
+		return ErrHasChildren
+	}
+
+	_, err := f.campaigns.remove(campaignID)
+	return err
+}
+
+// ListCampaigns returns campaigns matching the query filters, sorted by name.
+func (f *FsCampaignProvider) ListCampaigns(query dfm.CampaignQuery) ([]dfm.Campaign, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := f.campaigns.list(func(c dfm.Campaign) bool {
+		return matchesCampaignQuery(c, query)
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// ReadCampaignReadme returns the Markdown README of a campaign.
+// The README is read from campaigns/{uuid}/README.md.
+func (f *FsCampaignProvider) ReadCampaignReadme(campaignID string) (string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.campaigns.read(campaignID); !ok {
+		return "", ErrCampaignNotFound
+	}
+	return readDocument(filepath.Join(f.campaigns.dir, campaignID, ReadmeFile))
+}
+
+// ListCampaignResources returns the downloadable files of a campaign.
+// Resources are stored in campaigns/{uuid}/resources.
+func (f *FsCampaignProvider) ListCampaignResources(campaignID string) ([]dfm.Resource, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.campaigns.read(campaignID); !ok {
+		return nil, ErrCampaignNotFound
+	}
+	return listResources(filepath.Join(f.campaigns.dir, campaignID, ResourcesDir))
+}
+
+// OpenCampaignResource opens a downloadable file of a campaign for reading.
+func (f *FsCampaignProvider) OpenCampaignResource(campaignID, name string) (fs.File, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.campaigns.read(campaignID); !ok {
+		return nil, ErrCampaignNotFound
+	}
+	return openResource(filepath.Join(f.campaigns.dir, campaignID, ResourcesDir), name)
+}
+
+// CreateSession stores a new session.
+// The parent campaign must exist if CampaignID is set.
+func (f *FsCampaignProvider) CreateSession(session dfm.Session) error {
+	if err := validateName(session.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkCampaign(session.CampaignID); err != nil {
+		return err
+	}
+
+	session.CreatedAt, session.UpdatedAt = f.timestamps(session.CreatedAt)
+	return f.sessions.save(session)
+}
+
+// ReadSession retrieves a session by ID from the cache.
+func (f *FsCampaignProvider) ReadSession(sessionID string) (dfm.Session, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if session, ok := f.sessions.read(sessionID); ok {
+		return session, nil
+	}
+	return dfm.Session{}, ErrSessionNotFound
+}
+
+// UpdateSession modifies an existing session.
+// If the session name has changed, the file will be renamed.
+func (f *FsCampaignProvider) UpdateSession(session dfm.Session) error {
+	if err := validateName(session.Name); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing, ok := f.sessions.read(session.ID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if err := f.checkCampaign(session.CampaignID); err != nil {
+		return err
+	}
+
+	session.CreatedAt, session.UpdatedAt = f.timestamps(existing.CreatedAt)
+	return f.sessions.save(session)
+}
+
+// DeleteSession removes a session by ID.
+func (f *FsCampaignProvider) DeleteSession(sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	found, err := f.sessions.remove(sessionID)
+	if !found {
+		return ErrSessionNotFound
+	}
+	return err
+}
+
+// ListSessions returns sessions matching the query filters, sorted by play time.
+func (f *FsCampaignProvider) ListSessions(query dfm.SessionQuery) ([]dfm.Session, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := f.sessions.list(func(s dfm.Session) bool {
+		return matchesSessionQuery(s, query)
+	})
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PlayedAt.Equal(result[j].PlayedAt) {
+			return result[i].PlayedAt.Before(result[j].PlayedAt)
+		}
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result, nil
+}
+
+// ReadSessionNotes returns the Markdown notes of a session.
+// The notes are read from sessions/{uuid}/NOTES.md.
+func (f *FsCampaignProvider) ReadSessionNotes(sessionID string) (string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.sessions.read(sessionID); !ok {
+		return "", ErrSessionNotFound
+	}
+	return readDocument(filepath.Join(f.sessions.dir, sessionID, NotesFile))
+}
+
+// timestamps returns the creation and modification times for a saved document.
+// A zero creation time is replaced with the current time.
+func (f *FsCampaignProvider) timestamps(createdAt time.Time) (time.Time, time.Time) {
+	now := f.now()
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	return createdAt, now
+}
+
+// checkChronicle returns ErrChronicleNotFound if a non-empty chronicle ID does not exist.
+// The caller must hold f.mu.
+func (f *FsCampaignProvider) checkChronicle(chronicleID string) error {
+	if chronicleID == "" {
+		return nil
+	}
+	if _, ok := f.chronicles.read(chronicleID); !ok {
+		return ErrChronicleNotFound
+	}
+	return nil
+}
+
+// checkCampaign returns ErrCampaignNotFound if a non-empty campaign ID does not exist.
+// The caller must hold f.mu.
+func (f *FsCampaignProvider) checkCampaign(campaignID string) error {
+	if campaignID == "" {
+		return nil
+	}
+	if _, ok := f.campaigns.read(campaignID); !ok {
+		return ErrCampaignNotFound
+	}
+	return nil
+}
+
+// readDocument reads a Markdown document, returning ErrDocumentNotFound if it does not exist.
+func readDocument(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrDocumentNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read document %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// listResources lists the regular files in a resources folder.
+// A missing folder means there are no resources.
+func listResources(dir string) ([]dfm.Resource, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []dfm.Resource{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resources %s: %w", dir, err)
+	}
+
+	resources := []dfm.Resource{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+		resources = append(resources, dfm.Resource{
+			Name:       entry.Name(),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+	}
+	// os.ReadDir returns entries sorted by file name
+	return resources, nil
+}
+
+// openResource opens a regular file directly inside a resources folder.
+// Names with path separators or that do not refer to a regular file are not found.
+func openResource(dir, name string) (fs.File, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, ErrResourceNotFound
+	}
+
+	path := filepath.Join(dir, name)
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, ErrResourceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat resource %s: %w", path, err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resource %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// validateName checks if a chronicle, campaign or session name contains only valid characters.
+func validateName(name string) error {
+	if name == "" {
+		return nil // Empty names are allowed
+	}
+	if !dfm.ValidName(name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// matchesChronicleQuery checks if a chronicle matches the query filters.
+func matchesChronicleQuery(chronicle dfm.Chronicle, query dfm.ChronicleQuery) bool {
+	if query.Gamemaster != "" && chronicle.Gamemaster != query.Gamemaster {
+		return false
+	}
+	if query.Member != "" && !chronicle.IsMember(query.Member) {
+		return false
+	}
+	return true
+}
+
+// matchesCampaignQuery checks if a campaign matches the query filters.
+func matchesCampaignQuery(campaign dfm.Campaign, query dfm.CampaignQuery) bool {
+	if query.ChronicleID != "" && campaign.ChronicleID != query.ChronicleID {
+		return false
+	}
+	if query.Gamemaster != "" && campaign.Gamemaster != query.Gamemaster {
+		return false
+	}
+	if query.Member != "" && !campaign.IsMember(query.Member) {
+		return false
+	}
+	if query.Character != "" && !slices.Contains(campaign.Characters, query.Character) {
+		return false
+	}
+	return true
+}
+
+// matchesSessionQuery checks if a session matches the query filters.
+func matchesSessionQuery(session dfm.Session, query dfm.SessionQuery) bool {
+	if query.CampaignID != "" && session.CampaignID != query.CampaignID {
+		return false
+	}
+	if query.Gamemaster != "" && session.Gamemaster != query.Gamemaster {
+		return false
+	}
+	if query.Member != "" && !session.IsMember(query.Member) {
+		return false
+	}
+	if query.Character != "" && !slices.Contains(session.Characters, query.Character) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,448 @@
+package dfdb
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+const (
+	testChronicleID = "550e8400-e29b-41d4-a716-446655440100"
+	testCampaignID  = "550e8400-e29b-41d4-a716-446655440200"
+	testSessionID   = "550e8400-e29b-41d4-a716-446655440300"
+)
+
+// newTestCampaignProvider creates a provider with one chronicle, campaign and session
+func newTestCampaignProvider(t *testing.T) (*FsCampaignProvider, string) {
+	t.Helper()
+	dir := t.TempDir()
+	provider, err := NewFsCampaignProvider(dir)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if err := provider.CreateChronicle(dfm.Chronicle{ID: testChronicleID, Name: "Helsinki by Night", Gamemaster: "gm"}); err != nil {
+		t.Fatalf("Failed to create chronicle: %v", err)
+	}
+	if err := provider.CreateCampaign(dfm.Campaign{
+		ID:          testCampaignID,
+		ChronicleID: testChronicleID,
+		Name:        "Winter Court",
+		Gamemaster:  "gm",
+		Players:     []string{"alice", "bob"},
+		Characters:  []string{"char-1"},
+	}); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	if err := provider.CreateSession(dfm.Session{
+		ID:         testSessionID,
+		CampaignID: testCampaignID,
+		Name:       "Session 1",
+		Gamemaster: "gm",
+		Players:    []string{"alice"},
+	}); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	return provider, dir
+}
+
+func TestNewFsCampaignProviderCreatesDirectories(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "db")
+
+	if _, err := NewFsCampaignProvider(dir); err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	for _, sub := range []string{ChroniclesDir, CampaignsDir, SessionsDir} {
+		if _, err := os.Stat(filepath.Join(dir, sub)); os.IsNotExist(err) {
+			t.Errorf("Directory %s should have been created", sub)
+		}
+	}
+}
+
+func TestCampaignProviderCreateAndRead(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+
+	chronicle, err := provider.ReadChronicle(testChronicleID)
+	if err != nil {
+		t.Fatalf("Failed to read chronicle: %v", err)
+	}
+	if chronicle.Name != "Helsinki by Night" {
+		t.Errorf("Name mismatch: got %s, want Helsinki by Night", chronicle.Name)
+	}
+	if chronicle.CreatedAt.IsZero() || chronicle.UpdatedAt.IsZero() {
+		t.Error("Timestamps should be set on create")
+	}
+
+	campaign, err := provider.ReadCampaign(testCampaignID)
+	if err != nil {
+		t.Fatalf("Failed to read campaign: %v", err)
+	}
+	if campaign.ChronicleID != testChronicleID {
+		t.Errorf("ChronicleID mismatch: got %s, want %s", campaign.ChronicleID, testChronicleID)
+	}
+
+	session, err := provider.ReadSession(testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to read session: %v", err)
+	}
+	if session.CampaignID != testCampaignID {
+		t.Errorf("CampaignID mismatch: got %s, want %s", session.CampaignID, testCampaignID)
+	}
+
+	// Files follow the {name}_{uuid}.json convention
+	expected := []string{
+		filepath.Join(dir, ChroniclesDir, "helsinki_by_night_"+testChronicleID+".json"),
+		filepath.Join(dir, CampaignsDir, "winter_court_"+testCampaignID+".json"),
+		filepath.Join(dir, SessionsDir, "session_1_"+testSessionID+".json"),
+	}
+	for _, path := range expected {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Errorf("Expected file %s to exist", path)
+		}
+	}
+}
+
+func TestCampaignProviderNotFound(t *testing.T) {
+	provider, _ := NewFsCampaignProvider(t.TempDir())
+
+	if _, err := provider.ReadChronicle("missing"); err != ErrChronicleNotFound {
+		t.Errorf("Expected ErrChronicleNotFound, got %v", err)
+	}
+	if _, err := provider.ReadCampaign("missing"); err != ErrCampaignNotFound {
+		t.Errorf("Expected ErrCampaignNotFound, got %v", err)
+	}
+	if _, err := provider.ReadSession("missing"); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	if err := provider.UpdateCampaign(dfm.Campaign{ID: "missing"}); err != ErrCampaignNotFound {
+		t.Errorf("Expected ErrCampaignNotFound, got %v", err)
+	}
+	if err := provider.DeleteSession("missing"); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestCampaignProviderParentMustExist(t *testing.T) {
+	provider, _ := NewFsCampaignProvider(t.TempDir())
+
+	err := provider.CreateCampaign(dfm.Campaign{ID: testCampaignID, ChronicleID: "missing", Name: "Orphan"})
+	if err != ErrChronicleNotFound {
+		t.Errorf("Expected ErrChronicleNotFound, got %v", err)
+	}
+
+	err = provider.CreateSession(dfm.Session{ID: testSessionID, CampaignID: "missing", Name: "Orphan"})
+	if err != ErrCampaignNotFound {
+		t.Errorf("Expected ErrCampaignNotFound, got %v", err)
+	}
+}
+
+func TestCampaignProviderInvalidName(t *testing.T) {
+	provider, _ := NewFsCampaignProvider(t.TempDir())
+
+	if err := provider.CreateChronicle(dfm.Chronicle{ID: testChronicleID, Name: "Bad/Name"}); err != ErrInvalidName {
+		t.Errorf("Expected ErrInvalidName, got %v", err)
+	}
+}
+
+func TestCampaignProviderUpdateKeepsCreatedAtAndRenames(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+	original, _ := provider.ReadCampaign(testCampaignID)
+
+	later := original.UpdatedAt.Add(time.Hour)
+	provider.now = func() time.Time { return later }
+
+	updated := original
+	updated.Name = "Spring Court"
+	updated.CreatedAt = time.Time{}
+	if err := provider.UpdateCampaign(updated); err != nil {
+		t.Fatalf("Failed to update campaign: %v", err)
+	}
+
+	read, _ := provider.ReadCampaign(testCampaignID)
+	if !read.CreatedAt.Equal(original.CreatedAt) {
+		t.Errorf("CreatedAt changed: got %v, want %v", read.CreatedAt, original.CreatedAt)
+	}
+	if !read.UpdatedAt.Equal(later) {
+		t.Errorf("UpdatedAt mismatch: got %v, want %v", read.UpdatedAt, later)
+	}
+
+	oldFile := filepath.Join(dir, CampaignsDir, "winter_court_"+testCampaignID+".json")
+	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Error("Old file should have been deleted")
+	}
+	newFile := filepath.Join(dir, CampaignsDir, "spring_court_"+testCampaignID+".json")
+	if _, err := os.Stat(newFile); os.IsNotExist(err) {
+		t.Error("New file should exist")
+	}
+}
+
+func TestCampaignProviderDeleteWithChildren(t *testing.T) {
+	provider, _ := newTestCampaignProvider(t)
+
+	if err := provider.DeleteChronicle(testChronicleID); err != ErrHasChildren {
+		t.Errorf("Expected ErrHasChildren for chronicle, got %v", err)
+	}
+	if err := provider.DeleteCampaign(testCampaignID); err != ErrHasChildren {
+		t.Errorf("Expected ErrHasChildren for campaign, got %v", err)
+	}
+
+	// Deleting bottom-up succeeds
+	if err := provider.DeleteSession(testSessionID); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if err := provider.DeleteCampaign(testCampaignID); err != nil {
+		t.Fatalf("Failed to delete campaign: %v", err)
+	}
+	if err := provider.DeleteChronicle(testChronicleID); err != nil {
+		t.Fatalf("Failed to delete chronicle: %v", err)
+	}
+	if _, err := provider.ReadChronicle(testChronicleID); err != ErrChronicleNotFound {
+		t.Errorf("Expected ErrChronicleNotFound after deletion, got %v", err)
+	}
+}
+
+func TestCampaignProviderListQueries(t *testing.T) {
+	provider, _ := newTestCampaignProvider(t)
+
+	provider.CreateCampaign(dfm.Campaign{
+		ID:          "550e8400-e29b-41d4-a716-446655440201",
+		ChronicleID: testChronicleID,
+		Name:        "Autumn Court",
+		Gamemaster:  "othergm",
+		Players:     []string{"carol"},
+	})
+
+	tests := []struct {
+		name  string
+		query dfm.CampaignQuery
+		want  []string
+	}{
+		{"All sorted by name", dfm.CampaignQuery{}, []string{"Autumn Court", "Winter Court"}},
+		{"By chronicle", dfm.CampaignQuery{ChronicleID: testChronicleID}, []string{"Autumn Court", "Winter Court"}},
+		{"By gamemaster", dfm.CampaignQuery{Gamemaster: "gm"}, []string{"Winter Court"}},
+		{"Player member", dfm.CampaignQuery{Member: "carol"}, []string{"Autumn Court"}},
+		{"Gamemaster member", dfm.CampaignQuery{Member: "othergm"}, []string{"Autumn Court"}},
+		{"By character", dfm.CampaignQuery{Character: "char-1"}, []string{"Winter Court"}},
+		{"No match", dfm.CampaignQuery{Member: "dave"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := provider.ListCampaigns(tt.query)
+			if err != nil {
+				t.Fatalf("Failed to list: %v", err)
+			}
+			if len(result) != len(tt.want) {
+				t.Fatalf("Expected %d campaigns, got %d", len(tt.want), len(result))
+			}
+			for i, name := range tt.want {
+				if result[i].Name != name {
+					t.Errorf("Campaign %d: got %s, want %s", i, result[i].Name, name)
+				}
+			}
+		})
+	}
+
+	chronicles, _ := provider.ListChronicles(dfm.ChronicleQuery{Member: "gm"})
+	if len(chronicles) != 1 {
+		t.Errorf("Expected 1 chronicle for gm, got %d", len(chronicles))
+	}
+
+	sessions, _ := provider.ListSessions(dfm.SessionQuery{CampaignID: testCampaignID, Member: "alice"})
+	if len(sessions) != 1 {
+		t.Errorf("Expected 1 session for alice, got %d", len(sessions))
+	}
+}
+
+func TestCampaignProviderListSessionsSortedByPlayTime(t *testing.T) {
+	provider, _ := newTestCampaignProvider(t)
+
+	first, _ := provider.ReadSession(testSessionID)
+	first.PlayedAt = time.Date(2025, 12, 1, 19, 0, 0, 0, time.UTC)
+	provider.UpdateSession(first)
+
+	provider.CreateSession(dfm.Session{
+		ID:         "550e8400-e29b-41d4-a716-446655440301",
+		CampaignID: testCampaignID,
+		Name:       "Prologue",
+		PlayedAt:   time.Date(2025, 11, 1, 19, 0, 0, 0, time.UTC),
+	})
+
+	sessions, _ := provider.ListSessions(dfm.SessionQuery{CampaignID: testCampaignID})
+	if len(sessions) != 2 || sessions[0].Name != "Prologue" || sessions[1].Name != "Session 1" {
+		t.Errorf("Sessions not sorted by play time: %+v", sessions)
+	}
+}
+
+func TestCampaignProviderReadChronicleReadme(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+
+	if _, err := provider.ReadChronicleReadme(testChronicleID); err != ErrDocumentNotFound {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+
+	readmeDir := filepath.Join(dir, ChroniclesDir, testChronicleID)
+	os.MkdirAll(readmeDir, 0755)
+	os.WriteFile(filepath.Join(readmeDir, ReadmeFile), []byte("# Helsinki by Night\n"), 0644)
+
+	readme, err := provider.ReadChronicleReadme(testChronicleID)
+	if err != nil {
+		t.Fatalf("Failed to read README: %v", err)
+	}
+	if readme != "# Helsinki by Night\n" {
+		t.Errorf("README mismatch: got %q", readme)
+	}
+
+	if _, err := provider.ReadChronicleReadme("missing"); err != ErrChronicleNotFound {
+		t.Errorf("Expected ErrChronicleNotFound, got %v", err)
+	}
+}
+
+func TestCampaignProviderReadCampaignReadmeAndSessionNotes(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+
+	if _, err := provider.ReadCampaignReadme(testCampaignID); err != ErrDocumentNotFound {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+	if _, err := provider.ReadSessionNotes(testSessionID); err != ErrDocumentNotFound {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, CampaignsDir, testCampaignID), 0755)
+	os.WriteFile(filepath.Join(dir, CampaignsDir, testCampaignID, ReadmeFile), []byte("# Winter Court"), 0644)
+	os.MkdirAll(filepath.Join(dir, SessionsDir, testSessionID), 0755)
+	os.WriteFile(filepath.Join(dir, SessionsDir, testSessionID, NotesFile), []byte("# Session 1 notes"), 0644)
+
+	if readme, err := provider.ReadCampaignReadme(testCampaignID); err != nil || readme != "# Winter Court" {
+		t.Errorf("Unexpected campaign README %q, err %v", readme, err)
+	}
+	if notes, err := provider.ReadSessionNotes(testSessionID); err != nil || notes != "# Session 1 notes" {
+		t.Errorf("Unexpected session notes %q, err %v", notes, err)
+	}
+
+	if _, err := provider.ReadCampaignReadme("missing"); err != ErrCampaignNotFound {
+		t.Errorf("Expected ErrCampaignNotFound, got %v", err)
+	}
+	if _, err := provider.ReadSessionNotes("missing"); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestCampaignProviderResources(t *testing.T) {
+	provider, dir := newTestCampaignProvider(t)
+
+	// No resources folder means no resources
+	resources, err := provider.ListChronicleResources(testChronicleID)
+	if err != nil || len(resources) != 0 {
+		t.Fatalf("Expected no resources, got %+v, err %v", resources, err)
+	}
+
+	chronicleResources := filepath.Join(dir, ChroniclesDir, testChronicleID, ResourcesDir)
+	os.MkdirAll(filepath.Join(chronicleResources, "nested"), 0755)
+	os.WriteFile(filepath.Join(chronicleResources, "rules.pdf"), []byte("%PDF-1.7"), 0644)
+	os.WriteFile(filepath.Join(chronicleResources, "map.png"), []byte("PNG"), 0644)
+	os.WriteFile(filepath.Join(dir, ChroniclesDir, testChronicleID, ReadmeFile), []byte("# Secret"), 0644)
+
+	resources, err = provider.ListChronicleResources(testChronicleID)
+	if err != nil {
+		t.Fatalf("ListChronicleResources failed: %v", err)
+	}
+	if len(resources) != 2 || resources[0].Name != "map.png" || resources[1].Name != "rules.pdf" || resources[1].Size != 8 {
+		t.Errorf("Unexpected resources: %+v", resources)
+	}
+
+	file, err := provider.OpenChronicleResource(testChronicleID, "rules.pdf")
+	if err != nil {
+		t.Fatalf("OpenChronicleResource failed: %v", err)
+	}
+	file.Close()
+
+	// Only regular files directly in the resources folder can be opened
+	for _, name := range []string{"", ".", "..", "nested", "missing.pdf", "../README.md", "nested/../rules.pdf"} {
+		if _, err := provider.OpenChronicleResource(testChronicleID, name); err != ErrResourceNotFound {
+			t.Errorf("Expected ErrResourceNotFound for %q, got %v", name, err)
+		}
+	}
+
+	os.MkdirAll(filepath.Join(dir, CampaignsDir, testCampaignID, ResourcesDir), 0755)
+	os.WriteFile(filepath.Join(dir, CampaignsDir, testCampaignID, ResourcesDir, "handout.txt"), []byte("Clue"), 0644)
+	resources, err = provider.ListCampaignResources(testCampaignID)
+	if err != nil || len(resources) != 1 || resources[0].Name != "handout.txt" {
+		t.Errorf("Unexpected campaign resources %+v, err %v", resources, err)
+	}
+	if file, err := provider.OpenCampaignResource(testCampaignID, "handout.txt"); err != nil {
+		t.Errorf("OpenCampaignResource failed: %v", err)
+	} else {
+		file.Close()
+	}
+
+	if _, err := provider.ListChronicleResources("missing"); err != ErrChronicleNotFound {
+		t.Errorf("Expected ErrChronicleNotFound, got %v", err)
+	}
+	if _, err := provider.OpenCampaignResource("missing", "handout.txt"); err != ErrCampaignNotFound {
+		t.Errorf("Expected ErrCampaignNotFound, got %v", err)
+	}
+}
+
+func TestCampaignProviderLoadExistingFiles(t *testing.T) {
+	_, dir := newTestCampaignProvider(t)
+
+	provider2, err := NewFsCampaignProvider(dir)
+	if err != nil {
+		t.Fatalf("Failed to create second provider: %v", err)
+	}
+
+	if _, err := provider2.ReadChronicle(testChronicleID); err != nil {
+		t.Errorf("Failed to read chronicle from new provider: %v", err)
+	}
+	if _, err := provider2.ReadCampaign(testCampaignID); err != nil {
+		t.Errorf("Failed to read campaign from new provider: %v", err)
+	}
+	if _, err := provider2.ReadSession(testSessionID); err != nil {
+		t.Errorf("Failed to read session from new provider: %v", err)
+	}
+}
+
+func TestCampaignProviderIgnoresTrackerLogs(t *testing.T) {
+	_, dir := newTestCampaignProvider(t)
+
+	// Tracker logs share the sessions directory but are not sessions
+	trackers, _ := NewFsTrackerProvider(filepath.Join(dir, SessionsDir))
+	trackers.Append(testSessionID, dfm.TrackerEntry{Type: dfm.TrackerEntryRoll})
+
+	provider2, _ := NewFsCampaignProvider(dir)
+	sessions, _ := provider2.ListSessions(dfm.SessionQuery{})
+	if len(sessions) != 1 {
+		t.Errorf("Expected 1 session, got %d", len(sessions))
+	}
+}
+
+func TestCampaignProviderConcurrentAccess(t *testing.T) {
+	provider, _ := newTestCampaignProvider(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			provider.ReadCampaign(testCampaignID)
+			provider.ListSessions(dfm.SessionQuery{})
+		}()
+
+		go func() {
+			defer wg.Done()
+			campaign, _ := provider.ReadCampaign(testCampaignID)
+			provider.UpdateCampaign(campaign)
+		}()
+	}
+
+	wg.Wait()
+	// If we get here without deadlock or race, test passes
+}
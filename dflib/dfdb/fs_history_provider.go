@@ -0,0 +1,152 @@
+package dfdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// HistoryDir is the subdirectory of the database directory for character history files
+const HistoryDir = "history"
+
+// historyFileSuffix is appended to the character ID to form a history filename
+const historyFileSuffix = ".history.jsonl"
+
+// Character ID validation pattern for history filenames: only characters safe for filenames are allowed
+var validCharacterIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ErrHistoryNotFound is returned when a character has no history
+var ErrHistoryNotFound = errors.New("character history not found")
+
+// ErrInvalidCharacterID is returned when a character ID cannot be used as a filename
+var ErrInvalidCharacterID = errors.New("character ID contains invalid characters: only alphanumeric characters, dashes and underscores are allowed")
+
+// FsHistoryProvider implements the HistoryProvider interface using one JSON Lines file per character.
+// Files are named {characterID}.history.jsonl and are only ever appended to.
+type FsHistoryProvider struct {
+	mu  sync.Mutex
+	dir string // directory where history files are stored
+}
+
+// NewFsHistoryProvider creates a new filesystem-based character history provider.
+// If the directory does not exist, it will be created.
+func NewFsHistoryProvider(dir string) (*FsHistoryProvider, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	return &FsHistoryProvider{dir: dir}, nil
+}
+
+// Append adds an entry as a new line at the end of the character's history file.
+// The line is synced to disk before Append returns.
+func (f *FsHistoryProvider) Append(entry dfm.CharacterHistoryEntry) error {
+	if err := validateCharacterID(entry.CharacterID); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.historyPath(entry.CharacterID)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append to history %s: %w", path, err)
+	}
+	return nil
+}
+
+// Read retrieves the history of a character. Lines that cannot be parsed, such as
+// a line cut short by a crash, are skipped with a warning.
+func (f *FsHistoryProvider) Read(characterID string) ([]dfm.CharacterHistoryEntry, error) {
+	if err := validateCharacterID(characterID); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.historyPath(characterID)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrHistoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+
+	var entries []dfm.CharacterHistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry dfm.CharacterHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping line %d of %s: %v\n", line, path, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// List returns the sorted IDs of all characters that have a history.
+func (f *FsHistoryProvider) List() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	characterIDs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), historyFileSuffix) {
+			continue
+		}
+		characterIDs = append(characterIDs, strings.TrimSuffix(entry.Name(), historyFileSuffix))
+	}
+	sort.Strings(characterIDs)
+	return characterIDs, nil
+}
+
+// historyPath returns the path of a character's history file.
+func (f *FsHistoryProvider) historyPath(characterID string) string {
+	return filepath.Join(f.dir, characterID+historyFileSuffix)
+}
+
+// validateCharacterID checks if a character ID can safely be used as part of a filename.
+func validateCharacterID(characterID string) error {
+	if !validCharacterIDPattern.MatchString(characterID) {
+		return ErrInvalidCharacterID
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package dfdb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestHistoryAppendAndRead(t *testing.T) {
+	provider, err := NewFsHistoryProvider(filepath.Join(t.TempDir(), "history"))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	characterID := "550e8400-e29b-41d4-a716-446655440000"
+	first := dfm.CharacterHistoryEntry{
+		Sequence:    1,
+		CharacterID: characterID,
+		Type:        dfm.HistoryCreated,
+		Time:        time.Date(2026, 1, 2, 20, 0, 0, 0, time.UTC),
+		Username:    "alice",
+		Character:   dfm.Character{ID: characterID, Name: "Alice PC"},
+	}
+	second := dfm.CharacterHistoryEntry{
+		Sequence:    2,
+		CharacterID: characterID,
+		Type:        dfm.HistoryUpdated,
+		Time:        time.Date(2026, 1, 2, 21, 0, 0, 0, time.UTC),
+		Username:    "gm",
+		Changes:     []dfm.FieldChange{{Field: "fatePoint", Old: "0", New: "1"}},
+		Character:   dfm.Character{ID: characterID, Name: "Alice PC", FatePoint: 1, Revision: 1},
+	}
+	for _, entry := range []dfm.CharacterHistoryEntry{first, second} {
+		if err := provider.Append(entry); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+
+	entries, err := provider.Read(characterID)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if len(entries) != 2 || !reflect.DeepEqual(entries[1], second) || entries[0].Username != "alice" {
+		t.Errorf("Unexpected history: %+v", entries)
+	}
+
+	ids, err := provider.List()
+	if err != nil || !reflect.DeepEqual(ids, []string{characterID}) {
+		t.Errorf("List() = %v, %v", ids, err)
+	}
+}
+
+func TestHistoryReadNotFound(t *testing.T) {
+	provider, _ := NewFsHistoryProvider(t.TempDir())
+
+	if _, err := provider.Read("550e8400-e29b-41d4-a716-446655440000"); !errors.Is(err, ErrHistoryNotFound) {
+		t.Errorf("Expected ErrHistoryNotFound, got %v", err)
+	}
+	if _, err := provider.Read("../characters"); !errors.Is(err, ErrInvalidCharacterID) {
+		t.Errorf("Expected ErrInvalidCharacterID, got %v", err)
+	}
+	if err := provider.Append(dfm.CharacterHistoryEntry{CharacterID: "a/b"}); !errors.Is(err, ErrInvalidCharacterID) {
+		t.Errorf("Expected ErrInvalidCharacterID, got %v", err)
+	}
+}
+
+func TestHistorySkipsTornLines(t *testing.T) {
+	dir := t.TempDir()
+	provider, _ := NewFsHistoryProvider(dir)
+	characterID := "550e8400-e29b-41d4-a716-446655440000"
+
+	provider.Append(dfm.CharacterHistoryEntry{Sequence: 1, CharacterID: characterID, Type: dfm.HistoryCreated})
+
+	// A crash while appending leaves a partial line, later entries are still read
+	path := filepath.Join(dir, characterID+historyFileSuffix)
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString(`{"sequence": 2, "charac` + "\n")
+	file.Close()
+	provider.Append(dfm.CharacterHistoryEntry{Sequence: 2, CharacterID: characterID, Type: dfm.HistoryUpdated})
+
+	entries, err := provider.Read(characterID)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Type != dfm.HistoryUpdated {
+		t.Errorf("Expected the complete entries, got %+v", entries)
+	}
+}
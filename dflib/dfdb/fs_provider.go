@@ -9,20 +9,22 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/hkionline/dftui/dflib/dfm"
 )
 
 // UUID v4 pattern for file identification
 var uuidV4Pattern = regexp.MustCompile(`_([0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12})\.json$`)
 
-// Character name validation pattern: only alphanumeric and spaces allowed
-var validNamePattern = regexp.MustCompile(`^[a-zA-Z0-9 ]+$`)
-
 // ErrCharacterNotFound is returned when a character cannot be found
 var ErrCharacterNotFound = errors.New("character not found")
 
+// ErrCharacterExists is returned when creating a character with the ID of a stored one
+var ErrCharacterExists = errors.New("character already exists")
+
 // ErrInvalidCharacterName is returned when a character name contains invalid characters
 var ErrInvalidCharacterName = errors.New("character name contains invalid characters: only alphanumeric characters and spaces are allowed")
 
@@ -32,6 +34,12 @@ type FsProvider struct {
 	cache map[string]dfm.Character // map of cached characters by ID
 	files map[string]string        // map of filenames by character ID
 	dir   string                   // directory where character files are stored
+
+	watcher   *fsnotify.Watcher // directory watcher, nil when not watching
+	watchDone chan struct{}     // closed when the watch goroutine exits
+	debounce  time.Duration     // quiet time before a changed file is reloaded
+
+	changes changeFeed // subscribers to character changes
 }
 
 // NewFsProvider creates a new filesystem-based provider.
@@ -49,26 +57,34 @@ func NewFsProvider(dir string) (*FsProvider, error) {
 	}
 
 	return &FsProvider{
-		cache: cache,
-		files: files,
-		dir:   dir,
+		cache:    cache,
+		files:    files,
+		dir:      dir,
+		debounce: DefaultWatchDebounce,
 	}, nil
 }
 
-// Create stores a new character.
+// Create stores a new character. New characters start at revision 0,
+// restored ones keep the revision they are given.
 func (f *FsProvider) Create(character dfm.Character) error {
 	if err := validateCharacterName(character.Name); err != nil {
 		return err
 	}
+	character.Modified = time.Now().UTC()
+	character.EmptyLists()
 
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	if _, ok := f.files[character.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrCharacterExists, character.ID)
+	}
+
 	// Generate filename
 	filename := generateFilename(character.Name, character.ID)
 
 	// Write to file
-	if err := saveCharacter(character, filepath.Join(f.dir, filename)); err != nil {
+	if err := saveCharacter(character, filepath.Join(f.dir, filename), ""); err != nil {
 		return err
 	}
 
@@ -76,6 +92,7 @@ func (f *FsProvider) Create(character dfm.Character) error {
 	f.cache[character.ID] = character
 	f.files[character.ID] = filename
 
+	f.changes.notify(ChangeEvent{Type: ChangeCreated, CharacterID: character.ID, Character: character})
 	return nil
 }
 
@@ -90,9 +107,9 @@ func (f *FsProvider) Read(characterID string) (dfm.Character, error) {
 	return dfm.Character{}, ErrCharacterNotFound
 }
 
-// Update modifies an existing character.
+// Update modifies an existing character if it is still at expectedRevision.
 // If the character name has changed, the file will be renamed.
-func (f *FsProvider) Update(character dfm.Character) error {
+func (f *FsProvider) Update(character dfm.Character, expectedRevision int) error {
 	if err := validateCharacterName(character.Name); err != nil {
 		return err
 	}
@@ -106,28 +123,29 @@ func (f *FsProvider) Update(character dfm.Character) error {
 		return ErrCharacterNotFound
 	}
 
+	// Reject updates based on an old revision
+	stored := f.cache[character.ID]
+	if stored.Revision != expectedRevision {
+		return &StaleRevisionError{ExpectedRevision: expectedRevision, Current: stored}
+	}
+	character.Revision = stored.Revision + 1
+	character.Modified = time.Now().UTC()
+	character.EmptyLists()
+
 	// Generate new filename based on current name
 	newFilename := generateFilename(character.Name, character.ID)
 
-	// Write new file first (for safety)
+	// Replace the file, moving it first if the filename changed
 	newPath := filepath.Join(f.dir, newFilename)
-	if err := saveCharacter(character, newPath); err != nil {
+	if err := saveCharacter(character, newPath, filepath.Join(f.dir, oldFilename)); err != nil {
 		return err
 	}
 
-	// If filename changed, delete old file
-	if newFilename != oldFilename {
-		oldPath := filepath.Join(f.dir, oldFilename)
-		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
-			// Log but don't fail - the new file is already written
-			fmt.Fprintf(os.Stderr, "warning: failed to remove old file %s: %v\n", oldPath, err)
-		}
-	}
-
 	// Update cache
 	f.cache[character.ID] = character
 	f.files[character.ID] = newFilename
 
+	f.changes.notify(ChangeEvent{Type: ChangeUpdated, CharacterID: character.ID, Character: character})
 	return nil
 }
 
@@ -148,24 +166,31 @@ func (f *FsProvider) Delete(characterID string) error {
 	}
 
 	// Remove from cache
+	removed := f.cache[characterID]
 	delete(f.cache, characterID)
 	delete(f.files, characterID)
 
+	f.changes.notify(ChangeEvent{Type: ChangeDeleted, CharacterID: characterID, Character: removed})
 	return nil
 }
 
-// List returns characters matching the query filters.
+// Subscribe returns a channel receiving the characters created, updated and deleted
+// through the provider, and while watching also by other programs, and a function
+// that ends the subscription.
+func (f *FsProvider) Subscribe() (<-chan ChangeEvent, func()) {
+	return f.changes.Subscribe()
+}
+
+// List returns characters matching the query, sorted and paginated (see dfm.CharacterQuery.Apply).
 func (f *FsProvider) List(query dfm.CharacterQuery) ([]dfm.Character, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	var result []dfm.Character
+	characters := make([]dfm.Character, 0, len(f.cache))
 	for _, character := range f.cache {
-		if matchesQuery(character, query) {
-			result = append(result, character)
-		}
+		characters = append(characters, character)
 	}
-	return result, nil
+	return query.Apply(characters), nil
 }
 
 // validateCharacterName checks if a character name contains only valid characters.
@@ -173,7 +198,7 @@ func validateCharacterName(name string) error {
 	if name == "" {
 		return nil // Empty names are allowed
 	}
-	if !validNamePattern.MatchString(name) {
+	if !dfm.ValidName(name) {
 		return ErrInvalidCharacterName
 	}
 	return nil
@@ -201,20 +226,6 @@ func generateFilename(name, id string) string {
 	return fmt.Sprintf("%s_%s.json", safeName, id)
 }
 
-// matchesQuery checks if a character matches the query filters.
-func matchesQuery(character dfm.Character, query dfm.CharacterQuery) bool {
-	if query.Spirit != "" && character.Spirit != query.Spirit {
-		return false
-	}
-	if query.Player != "" && character.Player != query.Player {
-		return false
-	}
-	if query.Group != "" && character.Group != query.Group {
-		return false
-	}
-	return true
-}
-
 // loadCache loads all character files from the directory into memory.
 func loadCache(dir string) (map[string]dfm.Character, map[string]string, error) {
 	cache := make(map[string]dfm.Character)
@@ -245,6 +256,10 @@ func loadCache(dir string) (map[string]dfm.Character, map[string]string, error)
 			fmt.Fprintf(os.Stderr, "warning: failed to load character from %s: %v\n", path, err)
 			continue
 		}
+		// Files written by hand have no modification time, use the file's
+		if info, err := entry.Info(); err == nil && character.Modified.IsZero() {
+			character.Modified = info.ModTime().UTC()
+		}
 
 		cache[character.ID] = character
 		files[character.ID] = filename
@@ -265,20 +280,20 @@ func loadCharacter(path string) (dfm.Character, error) {
 	if err := json.Unmarshal(data, &character); err != nil {
 		return character, err
 	}
+	// Characters made from the templates in docs/ have blank placeholder entries
+	character.DropBlankEntries()
+	character.EmptyLists()
 
 	return character, nil
 }
 
-// saveCharacter writes a character to a JSON file.
-func saveCharacter(character dfm.Character, path string) error {
+// saveCharacter atomically writes a character to a JSON file, replacing the file at
+// oldPath if the character was stored under another name (see writeFileAtomic).
+func saveCharacter(character dfm.Character, path, oldPath string) error {
 	data, err := json.MarshalIndent(character, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal character: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return writeFileAtomic(path, data, oldPath)
 }
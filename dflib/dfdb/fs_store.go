@@ -0,0 +1,146 @@
+package dfdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fsStore is a cached collection of JSON documents stored one per file
+// using the {name}_{uuid}.json naming convention.
+// It is not safe for concurrent use; callers must synchronize access.
+type fsStore[T any] struct {
+	cache map[string]T      // map of cached documents by ID
+	files map[string]string // map of filenames by document ID
+	dir   string            // directory where document files are stored
+	id    func(T) string    // returns the ID of a document
+	name  func(T) string    // returns the name of a document
+}
+
+// newFsStore creates a store for a directory and loads existing documents into cache.
+// If the directory does not exist, it will be created.
+func newFsStore[T any](dir string, id, name func(T) string) (*fsStore[T], error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	s := &fsStore[T]{
+		cache: make(map[string]T),
+		files: make(map[string]string),
+		dir:   dir,
+		id:    id,
+		name:  name,
+	}
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("failed to load cache: %w", err)
+	}
+	return s, nil
+}
+
+// load reads all document files from the directory into the cache.
+func (s *fsStore[T]) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !uuidV4Pattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		item, err := loadDocument[T](path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to load %s: %v\n", path, err)
+			continue
+		}
+
+		s.cache[s.id(item)] = item
+		s.files[s.id(item)] = entry.Name()
+	}
+
+	return nil
+}
+
+// read returns a cached document by ID.
+func (s *fsStore[T]) read(id string) (T, bool) {
+	item, ok := s.cache[id]
+	return item, ok
+}
+
+// save writes a document to disk, renaming its file if the name has changed.
+func (s *fsStore[T]) save(item T) error {
+	id := s.id(item)
+	filename := generateFilename(s.name(item), id)
+
+	// Replace the file, moving it first if the filename changed
+	oldPath := ""
+	if oldFilename, ok := s.files[id]; ok {
+		oldPath = filepath.Join(s.dir, oldFilename)
+	}
+	if err := saveDocument(item, filepath.Join(s.dir, filename), oldPath); err != nil {
+		return err
+	}
+
+	s.cache[id] = item
+	s.files[id] = filename
+	return nil
+}
+
+// remove deletes a document file and drops it from the cache.
+// It returns false if the document does not exist.
+func (s *fsStore[T]) remove(id string) (bool, error) {
+	filename, ok := s.files[id]
+	if !ok {
+		return false, nil
+	}
+
+	path := filepath.Join(s.dir, filename)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return true, fmt.Errorf("failed to delete file %s: %w", path, err)
+	}
+
+	delete(s.cache, id)
+	delete(s.files, id)
+	return true, nil
+}
+
+// list returns the cached documents accepted by match.
+func (s *fsStore[T]) list(match func(T) bool) []T {
+	result := []T{}
+	for _, item := range s.cache {
+		if match(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// loadDocument reads a document from a JSON file.
+func loadDocument[T any](path string) (T, error) {
+	var item T
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return item, err
+	}
+
+	if err := json.Unmarshal(data, &item); err != nil {
+		return item, err
+	}
+
+	return item, nil
+}
+
+// saveDocument atomically writes a document to a JSON file, replacing the file at
+// oldPath if the document was stored under another name (see writeFileAtomic).
+func saveDocument[T any](item T, path, oldPath string) error {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	return writeFileAtomic(path, data, oldPath)
+}
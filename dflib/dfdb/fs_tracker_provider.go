@@ -0,0 +1,142 @@
+package dfdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// trackerFileSuffix is appended to the session ID to form a tracker log filename
+const trackerFileSuffix = ".tracker.json"
+
+// Session ID validation pattern: only characters safe for filenames are allowed
+var validSessionIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ErrTrackerNotFound is returned when a session has no tracker log
+var ErrTrackerNotFound = errors.New("session tracker log not found")
+
+// ErrInvalidSessionID is returned when a session ID cannot be used as a filename
+var ErrInvalidSessionID = errors.New("session ID contains invalid characters: only alphanumeric characters, dashes and underscores are allowed")
+
+// FsTrackerProvider implements the TrackerProvider interface using one JSON file per session.
+// Files are named {sessionID}.tracker.json.
+type FsTrackerProvider struct {
+	mu  sync.Mutex
+	dir string // directory where tracker log files are stored
+}
+
+// NewFsTrackerProvider creates a new filesystem-based tracker log provider.
+// If the directory does not exist, it will be created.
+func NewFsTrackerProvider(dir string) (*FsTrackerProvider, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	return &FsTrackerProvider{dir: dir}, nil
+}
+
+// Append adds entries to the end of a session's tracker log.
+func (f *FsTrackerProvider) Append(sessionID string, entries ...dfm.TrackerEntry) error {
+	if err := validateSessionID(sessionID); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.trackerPath(sessionID)
+	tracker, err := loadTracker(path)
+	if errors.Is(err, os.ErrNotExist) {
+		tracker = dfm.SessionTracker{SessionID: sessionID}
+	} else if err != nil {
+		return fmt.Errorf("failed to load tracker log %s: %w", path, err)
+	}
+
+	tracker.Entries = append(tracker.Entries, entries...)
+
+	return saveTracker(tracker, path)
+}
+
+// Read retrieves the tracker log of a session.
+func (f *FsTrackerProvider) Read(sessionID string) (dfm.SessionTracker, error) {
+	if err := validateSessionID(sessionID); err != nil {
+		return dfm.SessionTracker{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tracker, err := loadTracker(f.trackerPath(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return dfm.SessionTracker{}, ErrTrackerNotFound
+	}
+	return tracker, err
+}
+
+// List returns the sorted IDs of all sessions that have a tracker log.
+func (f *FsTrackerProvider) List() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	sessionIDs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), trackerFileSuffix) {
+			continue
+		}
+		sessionIDs = append(sessionIDs, strings.TrimSuffix(entry.Name(), trackerFileSuffix))
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs, nil
+}
+
+// trackerPath returns the path of a session's tracker log file.
+func (f *FsTrackerProvider) trackerPath(sessionID string) string {
+	return filepath.Join(f.dir, sessionID+trackerFileSuffix)
+}
+
+// validateSessionID checks if a session ID can safely be used as part of a filename.
+func validateSessionID(sessionID string) error {
+	if !validSessionIDPattern.MatchString(sessionID) {
+		return ErrInvalidSessionID
+	}
+	return nil
+}
+
+// loadTracker reads a tracker log from a JSON file.
+func loadTracker(path string) (dfm.SessionTracker, error) {
+	var tracker dfm.SessionTracker
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tracker, err
+	}
+
+	if err := json.Unmarshal(data, &tracker); err != nil {
+		return tracker, err
+	}
+
+	return tracker, nil
+}
+
+// saveTracker atomically writes a tracker log to a JSON file.
+func saveTracker(tracker dfm.SessionTracker, path string) error {
+	data, err := json.MarshalIndent(tracker, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracker log: %w", err)
+	}
+
+	return writeFileAtomic(path, data, "")
+}
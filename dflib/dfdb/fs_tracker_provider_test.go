@@ -0,0 +1,152 @@
+package dfdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestTrackerAppendAndRead(t *testing.T) {
+	dir := t.TempDir()
+	provider, err := NewFsTrackerProvider(dir)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	roll := dfm.TrackerEntry{
+		Type:        dfm.TrackerEntryRoll,
+		Time:        time.Date(2025, 12, 24, 20, 0, 0, 0, time.UTC),
+		Username:    "alice",
+		Character:   "Victor Joki",
+		Dice:        []int{1, 0, -1, 1},
+		Skill:       "fight",
+		SkillRating: 3,
+		Modifier:    2,
+		Invokes:     []dfm.Invoke{{Aspect: "Ancient Vampire Lord", Bonus: 2}},
+		Total:       6,
+	}
+	turn := dfm.TrackerEntry{
+		Type:     dfm.TrackerEntryTurn,
+		Time:     time.Date(2025, 12, 24, 20, 1, 0, 0, time.UTC),
+		Username: "gm",
+		Turn:     "Nathan Quincy",
+	}
+
+	if err := provider.Append("session-1", roll); err != nil {
+		t.Fatalf("Failed to append roll: %v", err)
+	}
+	if err := provider.Append("session-1", turn); err != nil {
+		t.Fatalf("Failed to append turn: %v", err)
+	}
+
+	tracker, err := provider.Read("session-1")
+	if err != nil {
+		t.Fatalf("Failed to read tracker log: %v", err)
+	}
+
+	if tracker.SessionID != "session-1" {
+		t.Errorf("SessionID mismatch: got %s, want session-1", tracker.SessionID)
+	}
+	if len(tracker.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(tracker.Entries))
+	}
+
+	got := tracker.Entries[0]
+	if got.Type != dfm.TrackerEntryRoll || got.Username != "alice" || got.Skill != "fight" || got.Total != 6 {
+		t.Errorf("Roll entry mismatch: %+v", got)
+	}
+	if len(got.Dice) != 4 || got.Dice[2] != -1 {
+		t.Errorf("Dice mismatch: got %v", got.Dice)
+	}
+	if len(got.Invokes) != 1 || got.Invokes[0].Aspect != "Ancient Vampire Lord" {
+		t.Errorf("Invokes mismatch: got %v", got.Invokes)
+	}
+	if got.Modifier != 2 {
+		t.Errorf("Modifier mismatch: got %d, want 2", got.Modifier)
+	}
+	if !got.Time.Equal(roll.Time) {
+		t.Errorf("Time mismatch: got %v, want %v", got.Time, roll.Time)
+	}
+	if tracker.Entries[1].Type != dfm.TrackerEntryTurn || tracker.Entries[1].Turn != "Nathan Quincy" {
+		t.Errorf("Turn entry mismatch: %+v", tracker.Entries[1])
+	}
+
+	// Log is stored in a per-session file
+	if _, err := os.Stat(filepath.Join(dir, "session-1.tracker.json")); os.IsNotExist(err) {
+		t.Error("Expected tracker log file to exist")
+	}
+}
+
+func TestTrackerPersistsAcrossProviders(t *testing.T) {
+	dir := t.TempDir()
+
+	provider1, _ := NewFsTrackerProvider(dir)
+	provider1.Append("session-1", dfm.TrackerEntry{Type: dfm.TrackerEntryRoll, Username: "alice"})
+
+	provider2, _ := NewFsTrackerProvider(dir)
+	provider2.Append("session-1", dfm.TrackerEntry{Type: dfm.TrackerEntryRoll, Username: "bob"})
+
+	tracker, err := provider2.Read("session-1")
+	if err != nil {
+		t.Fatalf("Failed to read tracker log: %v", err)
+	}
+	if len(tracker.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(tracker.Entries))
+	}
+	if tracker.Entries[0].Username != "alice" || tracker.Entries[1].Username != "bob" {
+		t.Errorf("Entries out of order: %+v", tracker.Entries)
+	}
+}
+
+func TestTrackerReadNotFound(t *testing.T) {
+	provider, _ := NewFsTrackerProvider(t.TempDir())
+
+	_, err := provider.Read("missing")
+	if err != ErrTrackerNotFound {
+		t.Errorf("Expected ErrTrackerNotFound, got %v", err)
+	}
+}
+
+func TestTrackerInvalidSessionID(t *testing.T) {
+	provider, _ := NewFsTrackerProvider(t.TempDir())
+
+	tests := []struct {
+		name      string
+		sessionID string
+		wantErr   bool
+	}{
+		{"Valid ID", "session-1", false},
+		{"Valid UUID", "550e8400-e29b-41d4-a716-446655440000", false},
+		{"Empty ID", "", true},
+		{"Path traversal", "../characters", true},
+		{"Slash", "a/b", true},
+		{"Space", "friday game", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.Append(tt.sessionID, dfm.TrackerEntry{Type: dfm.TrackerEntryRoll})
+			if (err == ErrInvalidSessionID) != tt.wantErr {
+				t.Errorf("Append() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrackerList(t *testing.T) {
+	provider, _ := NewFsTrackerProvider(t.TempDir())
+
+	provider.Append("session-b", dfm.TrackerEntry{Type: dfm.TrackerEntryRoll})
+	provider.Append("session-a", dfm.TrackerEntry{Type: dfm.TrackerEntryRoll})
+
+	sessionIDs, err := provider.List()
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(sessionIDs) != 2 || sessionIDs[0] != "session-a" || sessionIDs[1] != "session-b" {
+		t.Errorf("Unexpected session IDs: %v", sessionIDs)
+	}
+}
@@ -0,0 +1,150 @@
+package dfdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// UsersFile is the name of the user registry file in the database directory
+const UsersFile = "users.json"
+
+// ErrUserNotFound is returned when a user is not in the registry
+var ErrUserNotFound = errors.New("user not found")
+
+// FsUserProvider implements the UserProvider interface using a single JSON file
+// containing an array of users. The file is reloaded when it changes, so users
+// can be added or removed without restarting the server. A missing file is an
+// empty registry.
+type FsUserProvider struct {
+	mu      sync.Mutex
+	path    string
+	users   map[string]dfm.User // users by username
+	modTime time.Time           // modification time of the loaded file
+	size    int64               // size of the loaded file
+}
+
+// NewFsUserProvider creates a new filesystem-based user registry reading the given file.
+func NewFsUserProvider(path string) (*FsUserProvider, error) {
+	provider := &FsUserProvider{
+		path:  path,
+		users: make(map[string]dfm.User),
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return provider, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat user registry %s: %w", path, err)
+	}
+
+	users, err := loadUsers(path)
+	if err != nil {
+		return nil, err
+	}
+	provider.users = users
+	provider.modTime = info.ModTime()
+	provider.size = info.Size()
+
+	return provider, nil
+}
+
+// ReadUser retrieves a user by username.
+func (f *FsUserProvider) ReadUser(username string) (dfm.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.refresh()
+	user, ok := f.users[username]
+	if !ok {
+		return dfm.User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// ListUsers returns all registered users, sorted by username.
+func (f *FsUserProvider) ListUsers() ([]dfm.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.refresh()
+	users := make([]dfm.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// refresh reloads the registry if the file has changed since it was loaded.
+// If the file cannot be parsed, for example while it is being written,
+// the previously loaded users are kept.
+// The caller must hold f.mu.
+func (f *FsUserProvider) refresh() {
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		f.users = make(map[string]dfm.User)
+		f.modTime, f.size = time.Time{}, 0
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to stat user registry %s: %v\n", f.path, err)
+		return
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return
+	}
+
+	users, err := loadUsers(f.path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: keeping previous user registry: %v\n", err)
+		return
+	}
+	f.users = users
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+}
+
+// loadUsers reads and validates a user registry file.
+// Invalid and duplicate entries are skipped with a warning.
+func loadUsers(path string) (map[string]dfm.User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user registry %s: %w", path, err)
+	}
+
+	var list []dfm.User
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse user registry %s: %w", path, err)
+	}
+
+	users := make(map[string]dfm.User, len(list))
+	for i, user := range list {
+		if user.Username == "" {
+			fmt.Fprintf(os.Stderr, "warning: skipping user %d in %s: missing username\n", i, path)
+			continue
+		}
+		if _, ok := users[user.Username]; ok {
+			fmt.Fprintf(os.Stderr, "warning: skipping duplicate user %s in %s\n", user.Username, path)
+			continue
+		}
+		if user.Role == "" {
+			user.Role = dfm.RolePlayer
+		}
+		if !user.Role.Valid() {
+			fmt.Fprintf(os.Stderr, "warning: skipping user %s in %s: invalid role %q\n", user.Username, path, user.Role)
+			continue
+		}
+		users[user.Username] = user
+	}
+	return users, nil
+}
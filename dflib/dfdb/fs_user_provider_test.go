@@ -0,0 +1,123 @@
+package dfdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+const testUsersJSON = `[
+	{"username": "alice", "displayName": "Alice", "role": "player", "publicKeys": ["ssh-ed25519 AAAA alice@laptop"]},
+	{"username": "gm", "displayName": "The Storyteller", "role": "gamemaster"},
+	{"username": "root", "role": "admin"},
+	{"username": "bob"},
+	{"username": "alice", "role": "admin"},
+	{"username": "mallory", "role": "overlord"},
+	{"displayName": "Nobody"}
+]`
+
+func TestFsUserProviderReadAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), UsersFile)
+	if err := os.WriteFile(path, []byte(testUsersJSON), 0644); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	provider, err := NewFsUserProvider(path)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	alice, err := provider.ReadUser("alice")
+	if err != nil {
+		t.Fatalf("ReadUser failed: %v", err)
+	}
+	// The first entry wins over a duplicate
+	if alice.Role != dfm.RolePlayer || alice.Name() != "Alice" || len(alice.PublicKeys) != 1 {
+		t.Errorf("Unexpected user: %+v", alice)
+	}
+
+	// Role defaults to player
+	bob, err := provider.ReadUser("bob")
+	if err != nil || bob.Role != dfm.RolePlayer || bob.Name() != "bob" {
+		t.Errorf("Unexpected user %+v, err %v", bob, err)
+	}
+
+	// Invalid roles are skipped
+	if _, err := provider.ReadUser("mallory"); err != ErrUserNotFound {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+
+	users, err := provider.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers failed: %v", err)
+	}
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	if len(names) != 4 || names[0] != "alice" || names[1] != "bob" || names[2] != "gm" || names[3] != "root" {
+		t.Errorf("Unexpected users: %v", names)
+	}
+}
+
+func TestFsUserProviderMissingFile(t *testing.T) {
+	provider, err := NewFsUserProvider(filepath.Join(t.TempDir(), UsersFile))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if _, err := provider.ReadUser("alice"); err != ErrUserNotFound {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	if users, err := provider.ListUsers(); err != nil || len(users) != 0 {
+		t.Errorf("Expected no users, got %+v, err %v", users, err)
+	}
+}
+
+func TestFsUserProviderInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), UsersFile)
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	if _, err := NewFsUserProvider(path); err == nil {
+		t.Error("Expected error for invalid user registry")
+	}
+}
+
+func TestFsUserProviderReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), UsersFile)
+	os.WriteFile(path, []byte(`[{"username": "alice"}]`), 0644)
+
+	provider, err := NewFsUserProvider(path)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	// setModTime moves the file's modification time so the change is noticed
+	modTime := time.Now()
+	setModTime := func() {
+		modTime = modTime.Add(time.Second)
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	os.WriteFile(path, []byte(`[{"username": "alice"}, {"username": "bob", "role": "gamemaster"}]`), 0644)
+	setModTime()
+	if bob, err := provider.ReadUser("bob"); err != nil || bob.Role != dfm.RoleGamemaster {
+		t.Errorf("Expected reloaded user bob, got %+v, err %v", bob, err)
+	}
+
+	// A partially written file keeps the previous registry
+	os.WriteFile(path, []byte(`[{"username": "alice"}, {"usern`), 0644)
+	setModTime()
+	if _, err := provider.ReadUser("bob"); err != nil {
+		t.Errorf("Expected previous registry to be kept, got %v", err)
+	}
+
+	// Removing the file empties the registry
+	os.Remove(path)
+	if _, err := provider.ReadUser("alice"); err != ErrUserNotFound {
+		t.Errorf("Expected ErrUserNotFound after removal, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package dfdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// DefaultWatchDebounce is how long a character file must be left alone after a
+// change before it is reloaded, so that files are not read while being written
+const DefaultWatchDebounce = 200 * time.Millisecond
+
+// ErrAlreadyWatching is returned when Watch is called on a provider that is already watching
+var ErrAlreadyWatching = errors.New("provider is already watching its directory")
+
+// Watch starts watching the provider's directory for character files that are
+// added, changed or removed by other programs, e.g. a gamemaster copying files
+// into the directory. The cache is updated incrementally and subscribers are notified.
+// Changes made through the provider itself are reported once, by the method that made them.
+//
+// A file is reloaded once it has not changed for the debounce time. Files that
+// cannot be parsed, such as partially written ones, are skipped until they change again.
+// Call Close to stop watching.
+func (f *FsProvider) Watch() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.watcher != nil {
+		return ErrAlreadyWatching
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	if err := watcher.Add(f.dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch directory %s: %w", f.dir, err)
+	}
+
+	f.watcher = watcher
+	f.watchDone = make(chan struct{})
+	go f.watch(watcher, f.watchDone)
+	return nil
+}
+
+// Close stops watching the directory. It is safe to call Close on a provider that is not watching.
+func (f *FsProvider) Close() error {
+	f.mu.Lock()
+	watcher, done := f.watcher, f.watchDone
+	f.watcher = nil
+	f.mu.Unlock()
+
+	if watcher == nil {
+		return nil
+	}
+	err := watcher.Close()
+	<-done
+	return err
+}
+
+// watch handles file system events until the watcher is closed.
+// Each file is reloaded when its debounce timer fires.
+func (f *FsProvider) watch(watcher *fsnotify.Watcher, done chan struct{}) {
+	defer close(done)
+
+	timers := make(map[string]*time.Timer)
+	fired := make(chan string)
+	defer func() {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			filename := filepath.Base(event.Name)
+			if !uuidV4Pattern.MatchString(filename) {
+				continue
+			}
+			if timer, ok := timers[filename]; ok {
+				timer.Reset(f.debounce)
+				continue
+			}
+			timers[filename] = time.AfterFunc(f.debounce, func() {
+				select {
+				case fired <- filename:
+				case <-done:
+				}
+			})
+
+		case filename := <-fired:
+			delete(timers, filename)
+			if event, changed := f.reload(filename); changed {
+				f.changes.notify(event)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "warning: watching %s: %v\n", f.dir, err)
+		}
+	}
+}
+
+// reload brings the cache up to date with a character file that was added, changed
+// or removed. It returns the change and false if the cache did not change.
+func (f *FsProvider) reload(filename string) (ChangeEvent, bool) {
+	path := filepath.Join(f.dir, filename)
+	character, err := loadCharacter(path)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if errors.Is(err, os.ErrNotExist) {
+		// Removed, unless the character has been saved under another name since
+		for id, file := range f.files {
+			if file == filename {
+				removed := f.cache[id]
+				delete(f.cache, id)
+				delete(f.files, id)
+				return ChangeEvent{Type: ChangeDeleted, CharacterID: id, Character: removed}, true
+			}
+		}
+		return ChangeEvent{}, false
+	}
+	if err != nil || character.ID == "" {
+		// Partially written or broken, wait for the next change
+		return ChangeEvent{}, false
+	}
+
+	cached, exists := f.cache[character.ID]
+	f.files[character.ID] = filename
+	if exists && sameCharacter(cached, character) {
+		// Written by this provider or touched without changes
+		return ChangeEvent{}, false
+	}
+
+	// Edits outside the provider rarely update the revision, so make sure
+	// updates based on the previous version are rejected
+	if exists && character.Revision <= cached.Revision {
+		character.Revision = cached.Revision + 1
+	}
+	// or the modification time, so use the time the file was written
+	if character.Modified.IsZero() || exists && character.Modified.Equal(cached.Modified) {
+		character.Modified = time.Now().UTC()
+		if info, err := os.Stat(path); err == nil {
+			character.Modified = info.ModTime().UTC()
+		}
+	}
+	f.cache[character.ID] = character
+	event := ChangeEvent{Type: ChangeCreated, CharacterID: character.ID, Character: character}
+	if exists {
+		event.Type = ChangeUpdated
+	}
+	return event, true
+}
+
+// sameCharacter reports whether two characters are stored as the same JSON,
+// apart from the revision and the modification time
+func sameCharacter(a, b dfm.Character) bool {
+	a.Revision, b.Revision = 0, 0
+	a.Modified, b.Modified = time.Time{}, time.Time{}
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
@@ -0,0 +1,227 @@
+package dfdb
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// newWatchedProvider creates a provider watching a temporary directory with a short debounce
+func newWatchedProvider(t *testing.T) (*FsProvider, string) {
+	t.Helper()
+	dir := t.TempDir()
+	provider, err := NewFsProvider(dir)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	provider.debounce = 20 * time.Millisecond
+	if err := provider.Watch(); err != nil {
+		t.Fatalf("Failed to watch: %v", err)
+	}
+	t.Cleanup(func() { provider.Close() })
+	return provider, dir
+}
+
+// writeCharacterFile writes a character file like an external program would
+func writeCharacterFile(t *testing.T, dir string, character dfm.Character) string {
+	t.Helper()
+	data, err := json.Marshal(character)
+	if err != nil {
+		t.Fatalf("Failed to marshal character: %v", err)
+	}
+	path := filepath.Join(dir, generateFilename(character.Name, character.ID))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write character file: %v", err)
+	}
+	return path
+}
+
+// waitForEvent waits for the next change event
+func waitForEvent(t *testing.T, events <-chan ChangeEvent) ChangeEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a change event")
+		return ChangeEvent{}
+	}
+}
+
+// expectNoEvent checks that no change event arrives within a few debounce periods
+func expectNoEvent(t *testing.T, events <-chan ChangeEvent) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Errorf("Unexpected change event: %s %s", event.Type, event.CharacterID)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatchExternalChanges(t *testing.T) {
+	provider, dir := newWatchedProvider(t)
+	events, unsubscribe := provider.Subscribe()
+	defer unsubscribe()
+
+	character := dfm.Character{
+		ID:    "550e8400-e29b-41d4-a716-446655440000",
+		Name:  "Dropped In",
+		Group: "npc",
+	}
+
+	// A new file is added to the cache
+	path := writeCharacterFile(t, dir, character)
+	event := waitForEvent(t, events)
+	if event.Type != ChangeCreated || event.CharacterID != character.ID || event.Character.Name != "Dropped In" {
+		t.Errorf("Unexpected create event: %+v", event)
+	}
+	if _, err := provider.Read(character.ID); err != nil {
+		t.Errorf("Expected the new character in the cache: %v", err)
+	}
+
+	// A changed file updates the cache
+	character.Description = "Edited by hand"
+	writeCharacterFile(t, dir, character)
+	event = waitForEvent(t, events)
+	if event.Type != ChangeUpdated || event.Character.Description != "Edited by hand" {
+		t.Errorf("Unexpected update event: %+v", event)
+	}
+	read, _ := provider.Read(character.ID)
+	if read.Description != "Edited by hand" {
+		t.Errorf("Expected the cache to be updated, got %q", read.Description)
+	}
+
+	// Updates based on the version before the edit are stale
+	if err := provider.Update(character, 0); !errors.Is(err, ErrStaleRevision) {
+		t.Errorf("Expected ErrStaleRevision after an external edit, got %v", err)
+	}
+
+	// A removed file is removed from the cache
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	event = waitForEvent(t, events)
+	if event.Type != ChangeDeleted || event.CharacterID != character.ID {
+		t.Errorf("Unexpected delete event: %+v", event)
+	}
+	if _, err := provider.Read(character.ID); !errors.Is(err, ErrCharacterNotFound) {
+		t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+	}
+}
+
+func TestWatchToleratesPartialWrites(t *testing.T) {
+	provider, dir := newWatchedProvider(t)
+	events, unsubscribe := provider.Subscribe()
+	defer unsubscribe()
+
+	character := dfm.Character{
+		ID:   "550e8400-e29b-41d4-a716-446655440000",
+		Name: "Slow Writer",
+	}
+	data, _ := json.Marshal(character)
+	path := filepath.Join(dir, generateFilename(character.Name, character.ID))
+
+	// Half of the file is not loaded
+	if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	expectNoEvent(t, events)
+	if _, err := provider.Read(character.ID); !errors.Is(err, ErrCharacterNotFound) {
+		t.Errorf("Expected a partial file to be skipped, got %v", err)
+	}
+
+	// The complete file is
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if event := waitForEvent(t, events); event.Type != ChangeCreated {
+		t.Errorf("Expected a create event, got %+v", event)
+	}
+}
+
+func TestWatchDebouncesWrites(t *testing.T) {
+	provider, dir := newWatchedProvider(t)
+	provider.Close()
+	provider.debounce = 150 * time.Millisecond
+	if err := provider.Watch(); err != nil {
+		t.Fatalf("Failed to watch: %v", err)
+	}
+	events, unsubscribe := provider.Subscribe()
+	defer unsubscribe()
+
+	character := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Busy"}
+	for i := range 5 {
+		character.FatePoint = i
+		writeCharacterFile(t, dir, character)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	event := waitForEvent(t, events)
+	if event.Type != ChangeCreated || event.Character.FatePoint != 4 {
+		t.Errorf("Expected one create event with the last write, got %+v", event)
+	}
+	expectNoEvent(t, events)
+}
+
+func TestWatchReportsOwnChangesOnce(t *testing.T) {
+	provider, _ := newWatchedProvider(t)
+	events, unsubscribe := provider.Subscribe()
+	defer unsubscribe()
+
+	character := dfm.Character{
+		ID:          "550e8400-e29b-41d4-a716-446655440000",
+		Name:        "Own Character",
+		Disciplines: []dfm.Discipline{},
+	}
+	if err := provider.Create(character); err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+	if event := waitForEvent(t, events); event.Type != ChangeCreated {
+		t.Errorf("Expected a create event, got %+v", event)
+	}
+
+	character.Name = "Renamed Character"
+	if err := provider.Update(character, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+	if event := waitForEvent(t, events); event.Type != ChangeUpdated || event.Character.Name != "Renamed Character" {
+		t.Errorf("Expected an update event, got %+v", event)
+	}
+
+	if err := provider.Delete(character.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+	if event := waitForEvent(t, events); event.Type != ChangeDeleted || event.Character.Name != "Renamed Character" {
+		t.Errorf("Expected a delete event, got %+v", event)
+	}
+
+	// The watcher does not report the same changes again
+	expectNoEvent(t, events)
+}
+
+func TestWatchAndClose(t *testing.T) {
+	provider, _ := newWatchedProvider(t)
+
+	if err := provider.Watch(); !errors.Is(err, ErrAlreadyWatching) {
+		t.Errorf("Expected ErrAlreadyWatching, got %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Second Close failed: %v", err)
+	}
+
+	// Unsubscribing closes the channel
+	events, unsubscribe := provider.Subscribe()
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Error("Expected the events channel to be closed")
+	}
+}
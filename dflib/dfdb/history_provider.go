@@ -0,0 +1,15 @@
+package dfdb
+
+import (
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// HistoryProvider defines the interface for append-only character history storage backends.
+type HistoryProvider interface {
+	// Append adds an entry to the end of a character's history, creating the history if needed.
+	Append(entry dfm.CharacterHistoryEntry) error
+	// Read retrieves the history of a character, oldest entry first, returning an error if not found.
+	Read(characterID string) ([]dfm.CharacterHistoryEntry, error)
+	// List returns the IDs of all characters that have a history.
+	List() ([]string, error)
+}
@@ -0,0 +1,257 @@
+package dfdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// ErrHistoryEntryNotFound is returned when restoring a history entry that does not exist
+var ErrHistoryEntryNotFound = errors.New("history entry not found")
+
+// HistoryRecorder creates, updates and deletes characters through a Provider and
+// records every change in a HistoryProvider with the acting user, the time and the
+// changed fields. Changes made outside the application, such as files edited by hand,
+// are picked up from the provider's change events and recorded without a user.
+type HistoryRecorder struct {
+	mu          sync.Mutex // makes changes and records them one at a time
+	provider    Provider
+	history     HistoryProvider
+	now         func() time.Time // returns the time of a change
+	unsubscribe func()           // ends the subscription to the provider's changes
+	done        chan struct{}    // closed when recording the provider's changes stops
+	pending     map[string]int   // change events of changes made through the recorder, by changeKey
+}
+
+// NewHistoryRecorder creates a recorder for the characters of a provider.
+// Call Close to stop recording changes made outside the application.
+func NewHistoryRecorder(provider Provider, history HistoryProvider) *HistoryRecorder {
+	events, unsubscribe := provider.Subscribe()
+	r := &HistoryRecorder{
+		provider:    provider,
+		history:     history,
+		now:         time.Now,
+		unsubscribe: unsubscribe,
+		done:        make(chan struct{}),
+		pending:     make(map[string]int),
+	}
+	go r.recordEvents(events)
+	return r
+}
+
+// Close stops recording changes made outside the application.
+func (r *HistoryRecorder) Close() {
+	r.unsubscribe()
+	<-r.done
+}
+
+// Create stores a new character and records its creation by the user.
+func (r *HistoryRecorder) Create(username string, character dfm.Character) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.provider.Create(character); err != nil {
+		return err
+	}
+	r.expect(ChangeCreated, character)
+	r.record(dfm.CharacterHistoryEntry{Type: dfm.HistoryCreated, Username: username, Character: character})
+	return nil
+}
+
+// Update modifies a character whose stored revision is expectedRevision, see Provider,
+// and records the changed fields and the user.
+func (r *HistoryRecorder) Update(username string, character dfm.Character, expectedRevision int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, err := r.provider.Read(character.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.provider.Update(character, expectedRevision); err != nil {
+		return err
+	}
+	character.Revision = expectedRevision + 1
+	r.expect(ChangeUpdated, character)
+	r.record(dfm.CharacterHistoryEntry{
+		Type:      dfm.HistoryUpdated,
+		Username:  username,
+		Changes:   dfm.Diff(old, character),
+		Character: character,
+	})
+	return nil
+}
+
+// Delete removes a character and records the deleted version and the user,
+// so that the character can be restored.
+func (r *HistoryRecorder) Delete(username string, characterID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, err := r.provider.Read(characterID)
+	if err != nil {
+		return err
+	}
+	if err := r.provider.Delete(characterID); err != nil {
+		return err
+	}
+	r.expect(ChangeDeleted, old)
+	r.record(dfm.CharacterHistoryEntry{Type: dfm.HistoryDeleted, Username: username, Character: old})
+	return nil
+}
+
+// Restore brings back the version of a character stored in the history entry with
+// the given sequence. An existing character is updated to that version and a deleted
+// character is created again. The restore is recorded as a new history entry.
+// It returns the restored character.
+func (r *HistoryRecorder) Restore(username string, characterID string, sequence int) (dfm.Character, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.history.Read(characterID)
+	if err != nil {
+		return dfm.Character{}, err
+	}
+	if sequence < 1 || sequence > len(entries) {
+		return dfm.Character{}, fmt.Errorf("%w: %s #%d", ErrHistoryEntryNotFound, characterID, sequence)
+	}
+	version := entries[sequence-1].Character
+
+	current, err := r.provider.Read(characterID)
+	switch {
+	case err == nil:
+		version.Revision = current.Revision
+		if err := r.provider.Update(version, current.Revision); err != nil {
+			return dfm.Character{}, err
+		}
+		version.Revision++
+		r.expect(ChangeUpdated, version)
+
+	case errors.Is(err, ErrCharacterNotFound):
+		// Deleted, continue the revisions of the deleted version
+		current = entries[len(entries)-1].Character
+		version.Revision = current.Revision + 1
+		if err := r.provider.Create(version); err != nil {
+			return dfm.Character{}, err
+		}
+		r.expect(ChangeCreated, version)
+
+	default:
+		return dfm.Character{}, err
+	}
+
+	r.record(dfm.CharacterHistoryEntry{
+		Type:             dfm.HistoryRestored,
+		Username:         username,
+		Changes:          dfm.Diff(current, version),
+		RestoredSequence: sequence,
+		Character:        version,
+	})
+	return version, nil
+}
+
+// History returns the history of a character, oldest entry first.
+// Changes being made are recorded before the history is read.
+func (r *HistoryRecorder) History(characterID string) ([]dfm.CharacterHistoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.history.Read(characterID)
+}
+
+// Deleted returns the history entries of the deletions of characters that have not been restored.
+func (r *HistoryRecorder) Deleted() ([]dfm.CharacterHistoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	characterIDs, err := r.history.List()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []dfm.CharacterHistoryEntry{}
+	for _, characterID := range characterIDs {
+		entries, err := r.history.Read(characterID)
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+		last := entries[len(entries)-1]
+		if last.Type != dfm.HistoryDeleted {
+			continue
+		}
+		if _, err := r.provider.Read(characterID); errors.Is(err, ErrCharacterNotFound) {
+			deleted = append(deleted, last)
+		}
+	}
+	return deleted, nil
+}
+
+// record appends an entry to the history of its character, numbering it and
+// setting the time. The change has already been made, so failures are only logged.
+// The caller must hold the lock.
+func (r *HistoryRecorder) record(entry dfm.CharacterHistoryEntry) {
+	entry.CharacterID = entry.Character.ID
+	entry.Time = r.now()
+
+	entries, err := r.history.Read(entry.CharacterID)
+	if err != nil && !errors.Is(err, ErrHistoryNotFound) {
+		fmt.Fprintf(os.Stderr, "warning: failed to read history of %s: %v\n", entry.CharacterID, err)
+	}
+	entry.Sequence = len(entries) + 1
+
+	if err := r.history.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record %s of %s: %v\n", entry.Type, entry.CharacterID, err)
+	}
+}
+
+// recordEvents records the provider's changes that were not made through the recorder
+// until the subscription ends.
+func (r *HistoryRecorder) recordEvents(events <-chan ChangeEvent) {
+	defer close(r.done)
+	for event := range events {
+		r.mu.Lock()
+		r.recordEvent(event)
+		r.mu.Unlock()
+	}
+}
+
+// changeKey identifies a change event of a character revision
+func changeKey(changeType ChangeType, character dfm.Character) string {
+	return fmt.Sprintf("%s %s %d", changeType, character.ID, character.Revision)
+}
+
+// expect notes the change event the provider sent for a change made through the recorder,
+// so that the change is not recorded again. The caller must hold the lock.
+func (r *HistoryRecorder) expect(changeType ChangeType, character dfm.Character) {
+	r.pending[changeKey(changeType, character)]++
+}
+
+// recordEvent records a change made outside the application. The events of changes
+// made through the recorder are skipped, they are already in the history.
+// The caller must hold the lock.
+func (r *HistoryRecorder) recordEvent(event ChangeEvent) {
+	key := changeKey(event.Type, event.Character)
+	if r.pending[key] > 0 {
+		if r.pending[key]--; r.pending[key] == 0 {
+			delete(r.pending, key)
+		}
+		return
+	}
+
+	entry := dfm.CharacterHistoryEntry{Character: event.Character}
+	switch event.Type {
+	case ChangeCreated:
+		entry.Type = dfm.HistoryCreated
+	case ChangeUpdated:
+		entry.Type = dfm.HistoryUpdated
+		if entries, _ := r.history.Read(event.CharacterID); len(entries) > 0 {
+			entry.Changes = dfm.Diff(entries[len(entries)-1].Character, event.Character)
+		}
+	case ChangeDeleted:
+		entry.Type = dfm.HistoryDeleted
+	}
+	r.record(entry)
+}
@@ -0,0 +1,154 @@
+package dfdb
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// newTestHistoryRecorder creates a recorder for a watched provider in a temporary directory
+func newTestHistoryRecorder(t *testing.T) (*HistoryRecorder, *FsProvider, string) {
+	t.Helper()
+	provider, dir := newWatchedProvider(t)
+	history, err := NewFsHistoryProvider(filepath.Join(t.TempDir(), "history"))
+	if err != nil {
+		t.Fatalf("Failed to create history provider: %v", err)
+	}
+	recorder := NewHistoryRecorder(provider, history)
+	t.Cleanup(recorder.Close)
+	return recorder, provider, dir
+}
+
+// waitForHistory waits until a character's history has the given number of entries
+func waitForHistory(t *testing.T, recorder *HistoryRecorder, characterID string, count int) []dfm.CharacterHistoryEntry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries, _ := recorder.History(characterID)
+		if len(entries) >= count || time.Now().After(deadline) {
+			if len(entries) != count {
+				t.Fatalf("Expected %d history entries, got %d: %+v", count, len(entries), entries)
+			}
+			return entries
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHistoryRecorderRecordsChanges(t *testing.T) {
+	recorder, _, _ := newTestHistoryRecorder(t)
+
+	character := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Alice PC"}
+	if err := recorder.Create("alice", character); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	character.FatePoint = 2
+	if err := recorder.Update("gm", character, 0); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := recorder.Update("gm", character, 0); !errors.Is(err, ErrStaleRevision) {
+		t.Errorf("Expected ErrStaleRevision, got %v", err)
+	}
+	if err := recorder.Delete("admin", character.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	// Own changes are recorded once, also after the watcher has seen them
+	time.Sleep(100 * time.Millisecond)
+	entries := waitForHistory(t, recorder, character.ID, 3)
+
+	want := []struct {
+		entryType dfm.HistoryEntryType
+		username  string
+		revision  int
+	}{
+		{dfm.HistoryCreated, "alice", 0},
+		{dfm.HistoryUpdated, "gm", 1},
+		{dfm.HistoryDeleted, "admin", 1},
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.Sequence != i+1 || entry.Type != w.entryType || entry.Username != w.username || entry.Character.Revision != w.revision {
+			t.Errorf("Entry %d = %d %s by %q at revision %d, want %s by %q at revision %d",
+				i, entry.Sequence, entry.Type, entry.Username, entry.Character.Revision, w.entryType, w.username, w.revision)
+		}
+		if entry.Time.IsZero() {
+			t.Errorf("Entry %d has no time", i)
+		}
+	}
+	if changes := entries[1].Changes; len(changes) != 1 || changes[0] != (dfm.FieldChange{Field: "fatePoint", Old: "0", New: "2"}) {
+		t.Errorf("Unexpected update changes: %+v", changes)
+	}
+}
+
+func TestHistoryRecorderRestore(t *testing.T) {
+	recorder, provider, _ := newTestHistoryRecorder(t)
+
+	character := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Alice PC", FatePoint: 3}
+	recorder.Create("alice", character)
+	character.FatePoint = 0
+	recorder.Update("alice", character, 0)
+
+	// Restore the created version of an existing character
+	restored, err := recorder.Restore("gm", character.ID, 1)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored.FatePoint != 3 || restored.Revision != 2 {
+		t.Errorf("Expected fate point 3 at revision 2, got %+v", restored)
+	}
+	if read, _ := provider.Read(character.ID); read.FatePoint != 3 || read.Revision != 2 {
+		t.Errorf("Restored version not stored: %+v", read)
+	}
+
+	// Restore a deleted character
+	recorder.Delete("alice", character.ID)
+	deleted, err := recorder.Deleted()
+	if err != nil || len(deleted) != 1 || deleted[0].Sequence != 4 {
+		t.Fatalf("Expected the deleted character, got %+v, %v", deleted, err)
+	}
+	restored, err = recorder.Restore("gm", character.ID, deleted[0].Sequence)
+	if err != nil {
+		t.Fatalf("Restore of a deleted character failed: %v", err)
+	}
+	if read, err := provider.Read(character.ID); err != nil || read.FatePoint != 3 || read.Revision != 3 {
+		t.Errorf("Deleted character not restored: %+v, %v", read, err)
+	}
+	if deleted, _ := recorder.Deleted(); len(deleted) != 0 {
+		t.Errorf("Restored character still listed as deleted: %+v", deleted)
+	}
+
+	entries := waitForHistory(t, recorder, character.ID, 5)
+	if last := entries[4]; last.Type != dfm.HistoryRestored || last.RestoredSequence != 4 || last.Username != "gm" {
+		t.Errorf("Unexpected restore entry: %+v", last)
+	}
+	if changes := entries[2].Changes; len(changes) != 1 || changes[0].Field != "fatePoint" {
+		t.Errorf("Expected the restore to record the fate point change, got %+v", changes)
+	}
+
+	if _, err := recorder.Restore("gm", character.ID, 9); !errors.Is(err, ErrHistoryEntryNotFound) {
+		t.Errorf("Expected ErrHistoryEntryNotFound, got %v", err)
+	}
+}
+
+func TestHistoryRecorderRecordsExternalChanges(t *testing.T) {
+	recorder, _, dir := newTestHistoryRecorder(t)
+
+	character := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Dropped In"}
+	writeCharacterFile(t, dir, character)
+	waitForHistory(t, recorder, character.ID, 1)
+
+	character.FatePoint = 1
+	writeCharacterFile(t, dir, character)
+	entries := waitForHistory(t, recorder, character.ID, 2)
+
+	if entries[0].Type != dfm.HistoryCreated || entries[1].Type != dfm.HistoryUpdated {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+	if entries[1].Username != "" || len(entries[1].Changes) != 1 || entries[1].Changes[0].Field != "fatePoint" {
+		t.Errorf("External update should be recorded without a user: %+v", entries[1])
+	}
+}
@@ -0,0 +1,174 @@
+package dfdb
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// MemoryProvider implements the Provider interface by keeping characters in memory.
+// Nothing is stored, so it suits tests and throwaway demo servers. Characters are
+// copied when stored and read, so callers never share lists with the provider.
+type MemoryProvider struct {
+	mu         sync.RWMutex
+	characters map[string]dfm.Character // map of characters by ID
+
+	changes changeFeed // subscribers to character changes
+}
+
+// NewMemoryProvider creates an in-memory provider seeded with the given characters.
+func NewMemoryProvider(characters []dfm.Character) *MemoryProvider {
+	m := &MemoryProvider{characters: make(map[string]dfm.Character, len(characters))}
+	for _, character := range characters {
+		character = character.Clone()
+		character.EmptyLists()
+		m.characters[character.ID] = character
+	}
+	return m
+}
+
+// NewMemoryProviderFromFiles creates an in-memory provider seeded with the characters in
+// JSON files. A directory path seeds all .json files in the directory. Characters without
+// an ID, such as the templates in docs/, get a new one.
+func NewMemoryProviderFromFiles(paths ...string) (*MemoryProvider, error) {
+	var characters []dfm.Character
+	for _, path := range paths {
+		files, err := characterFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			character, err := loadCharacter(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load character from %s: %w", file, err)
+			}
+			if character.ID == "" {
+				character.ID = dfm.NewID()
+			}
+			characters = append(characters, character)
+		}
+	}
+	return NewMemoryProvider(characters), nil
+}
+
+// characterFiles returns the path of a file, or the sorted .json files of a directory.
+func characterFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// Create stores a new character. New characters start at revision 0,
+// restored ones keep the revision they are given.
+func (m *MemoryProvider) Create(character dfm.Character) error {
+	if err := validateCharacterName(character.Name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.characters[character.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrCharacterExists, character.ID)
+	}
+	character.Modified = time.Now().UTC()
+	character.EmptyLists()
+	m.characters[character.ID] = character.Clone()
+
+	m.changes.notify(ChangeEvent{Type: ChangeCreated, CharacterID: character.ID, Character: character.Clone()})
+	return nil
+}
+
+// Read retrieves a character by ID.
+func (m *MemoryProvider) Read(characterID string) (dfm.Character, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if character, ok := m.characters[characterID]; ok {
+		return character.Clone(), nil
+	}
+	return dfm.Character{}, ErrCharacterNotFound
+}
+
+// Update modifies an existing character if it is still at expectedRevision.
+func (m *MemoryProvider) Update(character dfm.Character, expectedRevision int) error {
+	if err := validateCharacterName(character.Name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.characters[character.ID]
+	if !ok {
+		return ErrCharacterNotFound
+	}
+
+	// Reject updates based on an old revision
+	if stored.Revision != expectedRevision {
+		return &StaleRevisionError{ExpectedRevision: expectedRevision, Current: stored.Clone()}
+	}
+	character.Revision = stored.Revision + 1
+	character.Modified = time.Now().UTC()
+	character.EmptyLists()
+	m.characters[character.ID] = character.Clone()
+
+	m.changes.notify(ChangeEvent{Type: ChangeUpdated, CharacterID: character.ID, Character: character.Clone()})
+	return nil
+}
+
+// Delete removes a character by ID.
+func (m *MemoryProvider) Delete(characterID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed, ok := m.characters[characterID]
+	if !ok {
+		return ErrCharacterNotFound
+	}
+	delete(m.characters, characterID)
+
+	m.changes.notify(ChangeEvent{Type: ChangeDeleted, CharacterID: characterID, Character: removed})
+	return nil
+}
+
+// List returns characters matching the query, sorted and paginated (see dfm.CharacterQuery.Apply).
+func (m *MemoryProvider) List(query dfm.CharacterQuery) ([]dfm.Character, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := query.Apply(slices.Collect(maps.Values(m.characters)))
+	for i := range result {
+		result[i] = result[i].Clone()
+	}
+	return result, nil
+}
+
+// Subscribe returns a channel receiving the characters created, updated and deleted
+// through the provider, and a function that ends the subscription.
+func (m *MemoryProvider) Subscribe() (<-chan ChangeEvent, func()) {
+	return m.changes.Subscribe()
+}
@@ -0,0 +1,85 @@
+package dfdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestNewMemoryProviderSeeded(t *testing.T) {
+	seed := []dfm.Character{
+		{ID: "id-1", Name: "Char One", Spirit: "human", Tags: []string{"seeded"}},
+		{ID: "id-2", Name: "Char Two", Spirit: "vampire"},
+	}
+	provider := NewMemoryProvider(seed)
+
+	result, err := provider.List(dfm.CharacterQuery{})
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 characters, got %d", len(result))
+	}
+
+	// The seed slice is copied
+	seed[0].Tags[0] = "changed"
+	if read, _ := provider.Read("id-1"); read.Tags[0] != "seeded" {
+		t.Errorf("Stored character shares lists with the seed: %v", read.Tags)
+	}
+}
+
+func TestMemoryProviderReturnsCopies(t *testing.T) {
+	provider := NewMemoryProvider(nil)
+	char := dfm.NewCharacter(dfm.SpiritVampire)
+	char.Name = "Original"
+	provider.Create(char)
+
+	read, _ := provider.Read(char.ID)
+	read.Skills[0].Rating = 5
+	read.Name = "Changed"
+
+	stored, _ := provider.Read(char.ID)
+	if stored.Name != "Original" || stored.Skills[0].Rating != 0 {
+		t.Errorf("Changing a read character changed the stored one: %s %d", stored.Name, stored.Skills[0].Rating)
+	}
+}
+
+func TestNewMemoryProviderFromFiles(t *testing.T) {
+	// The character templates in docs/ have no IDs
+	docs := filepath.Join("..", "..", "docs")
+	provider, err := NewMemoryProviderFromFiles(docs)
+	if err != nil {
+		t.Fatalf("Failed to seed from %s: %v", docs, err)
+	}
+
+	result, _ := provider.List(dfm.CharacterQuery{})
+	if len(result) != 3 {
+		t.Fatalf("Expected the 3 character templates, got %d", len(result))
+	}
+	for _, character := range result {
+		if character.ID == "" {
+			t.Errorf("Expected %s character to get an ID", character.Spirit)
+		}
+		// The blank placeholder entries of the templates are dropped
+		if err := character.Validate(); err != nil {
+			t.Errorf("Expected the %s template to be valid: %v", character.Spirit, err)
+		}
+	}
+	if vampires, _ := provider.List(dfm.CharacterQuery{Spirit: "vampire"}); len(vampires) != 1 {
+		t.Errorf("Expected 1 vampire, got %d", len(vampires))
+	}
+
+	// Files can be listed one by one
+	provider, err = NewMemoryProviderFromFiles(filepath.Join(docs, "ghoul_character.json"))
+	if err != nil {
+		t.Fatalf("Failed to seed from a file: %v", err)
+	}
+	if result, _ := provider.List(dfm.CharacterQuery{}); len(result) != 1 || result[0].Spirit != "ghoul" {
+		t.Errorf("Expected the ghoul template, got %+v", result)
+	}
+
+	if _, err := NewMemoryProviderFromFiles(filepath.Join(docs, "missing.json")); err == nil {
+		t.Error("Expected error for a missing seed file")
+	}
+}
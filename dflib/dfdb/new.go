@@ -1,17 +1,41 @@
 package dfdb
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
 	// FileSystemProvider is the provider type for filesystem-based storage
 	FileSystemProvider = "filesystem"
+	// SqliteDatabaseProvider is the provider type for an embedded SQLite database
+	SqliteDatabaseProvider = "sqlite"
+	// InMemoryProvider is the provider type for characters kept in memory only
+	InMemoryProvider = "memory"
 )
 
-// New creates a new Provider based on the configuration.
-// If the provider type is unknown, it defaults to filesystem provider.
-func New(config ProviderConfiguration) (Provider, error) {
+// ErrUnknownProvider is returned when the configured provider type is not supported
+var ErrUnknownProvider = errors.New("unknown provider type")
+
+// Watcher is implemented by providers that can pick up characters changed outside the application.
+type Watcher interface {
+	// Watch starts sending change events for changes made by other programs.
+	Watch() error
+	// Close stops watching.
+	Close() error
+}
+
+// NewProvider creates a new Provider of the type selected by the Provider field of the configuration.
+// An empty type selects the filesystem provider.
+func NewProvider(config ProviderConfiguration) (Provider, error) {
 	switch config.Provider {
-	case FileSystemProvider:
+	case FileSystemProvider, "":
 		return NewFsProvider(config.Filesystem.Directory)
+	case SqliteDatabaseProvider:
+		return NewSqliteProvider(config.Sqlite.Path)
+	case InMemoryProvider:
+		return NewMemoryProviderFromFiles(config.Memory.Seed...)
 	default:
-		return NewFsProvider(config.Filesystem.Directory)
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, config.Provider)
 	}
 }
@@ -0,0 +1,59 @@
+package dfdb
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestNewProvider(t *testing.T) {
+	for _, providerType := range []string{FileSystemProvider, ""} {
+		provider, err := NewProvider(ProviderConfiguration{
+			Provider:   providerType,
+			Filesystem: FsProviderConfiguration{Directory: t.TempDir()},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create %q provider: %v", providerType, err)
+		}
+		if _, ok := provider.(*FsProvider); !ok {
+			t.Errorf("Expected %q to select the filesystem provider, got %T", providerType, provider)
+		}
+	}
+}
+
+func TestNewProviderUnknownType(t *testing.T) {
+	_, err := NewProvider(ProviderConfiguration{Provider: "cloud"})
+	if !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+}
+
+func TestNewProviderSqlite(t *testing.T) {
+	provider, err := NewProvider(ProviderConfiguration{
+		Provider: SqliteDatabaseProvider,
+		Sqlite:   SqliteProviderConfiguration{Path: filepath.Join(t.TempDir(), "characters.db")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create sqlite provider: %v", err)
+	}
+	sqlite, ok := provider.(*SqliteProvider)
+	if !ok {
+		t.Fatalf("Expected the SQLite provider, got %T", provider)
+	}
+	sqlite.Close()
+}
+
+func TestNewProviderMemory(t *testing.T) {
+	provider, err := NewProvider(ProviderConfiguration{
+		Provider: InMemoryProvider,
+		Memory:   MemoryProviderConfiguration{Seed: []string{filepath.Join("..", "..", "docs", "human_character.json")}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create memory provider: %v", err)
+	}
+	if result, _ := provider.List(dfm.CharacterQuery{}); len(result) != 1 {
+		t.Errorf("Expected 1 seeded character, got %d", len(result))
+	}
+}
@@ -2,29 +2,87 @@
 package dfdb
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/hkionline/dftui/dflib/dfm"
 )
 
+// ErrStaleRevision is returned, as a *StaleRevisionError, when updating a character
+// that someone else has updated since it was read
+var ErrStaleRevision = errors.New("character has been changed by someone else")
+
+// StaleRevisionError is returned when an update is based on an old revision of a character.
+type StaleRevisionError struct {
+	// ExpectedRevision is the revision the update was based on
+	ExpectedRevision int
+	// Current is the character as currently stored
+	Current dfm.Character
+}
+
+// Error describes the conflicting revisions
+func (e *StaleRevisionError) Error() string {
+	return fmt.Sprintf("%v: %s is at revision %d, not %d", ErrStaleRevision, e.Current.Name, e.Current.Revision, e.ExpectedRevision)
+}
+
+// Is makes errors.Is(err, ErrStaleRevision) true for stale revision errors
+func (e *StaleRevisionError) Is(target error) bool {
+	return target == ErrStaleRevision
+}
+
 // Provider defines the interface for character storage backends.
 type Provider interface {
-	// Create stores a new character and returns an error if it fails.
+	// Create stores a new character and returns an error if it fails, ErrCharacterExists if
+	// a character with the same ID is stored. Nil lists are stored as empty lists.
 	Create(character dfm.Character) error
 	// Read retrieves a character by ID, returning an error if not found.
 	Read(characterID string) (dfm.Character, error)
-	// Update modifies an existing character, returning an error if it fails.
-	Update(character dfm.Character) error
+	// Update modifies an existing character whose stored revision is expectedRevision.
+	// The stored character gets the next revision. A *StaleRevisionError is returned
+	// if the character has been updated since expectedRevision was read. Nil lists are
+	// stored as empty lists.
+	Update(character dfm.Character, expectedRevision int) error
 	// Delete removes a character by ID, returning an error if not found.
 	Delete(characterID string) error
 	// List returns characters matching the query filters.
 	List(query dfm.CharacterQuery) ([]dfm.Character, error)
+	// Subscribe returns a channel of character changes and a function that ends the subscription.
+	// Events are dropped for subscribers that do not keep up.
+	Subscribe() (<-chan ChangeEvent, func())
+}
+
+// ChangeType identifies the kind of a character change.
+type ChangeType string
+
+const (
+	// ChangeCreated is sent when a character is added
+	ChangeCreated ChangeType = "create"
+	// ChangeUpdated is sent when a character is modified
+	ChangeUpdated ChangeType = "update"
+	// ChangeDeleted is sent when a character is removed
+	ChangeDeleted ChangeType = "delete"
+)
+
+// ChangeEvent describes a change to a stored character.
+type ChangeEvent struct {
+	// Type is the kind of the change
+	Type ChangeType
+	// CharacterID identifies the changed character
+	CharacterID string
+	// Character is the character after the change, or the removed character for deletes
+	Character dfm.Character
 }
 
 // ProviderConfiguration holds configuration for all provider types.
 type ProviderConfiguration struct {
-	// Provider is the type of provider: "filesystem"
+	// Provider is the type of provider: "filesystem" (default), "sqlite" or "memory"
 	Provider string `yaml:"provider" json:"provider"`
 	// Filesystem contains filesystem provider configuration
 	Filesystem FsProviderConfiguration `yaml:"filesystem" json:"filesystem"`
+	// Sqlite contains SQLite provider configuration
+	Sqlite SqliteProviderConfiguration `yaml:"sqlite" json:"sqlite"`
+	// Memory contains in-memory provider configuration
+	Memory MemoryProviderConfiguration `yaml:"memory" json:"memory"`
 }
 
 // FsProviderConfiguration holds configuration for the filesystem provider.
@@ -32,3 +90,15 @@ type FsProviderConfiguration struct {
 	// Directory is the path to store character JSON files
 	Directory string `yaml:"directory" json:"directory"`
 }
+
+// SqliteProviderConfiguration holds configuration for the SQLite provider.
+type SqliteProviderConfiguration struct {
+	// Path is the path to the SQLite database file
+	Path string `yaml:"path" json:"path"`
+}
+
+// MemoryProviderConfiguration holds configuration for the in-memory provider.
+type MemoryProviderConfiguration struct {
+	// Seed lists character JSON files and directories of them loaded at startup
+	Seed []string `yaml:"seed" json:"seed"`
+}
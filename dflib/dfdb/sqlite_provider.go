@@ -0,0 +1,572 @@
+package dfdb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" database/sql driver
+)
+
+// sqliteSchema creates the tables of a character database. Lists of a character are
+// stored in their own tables, ordered by position, and removed with the character.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS characters (
+	id TEXT PRIMARY KEY,
+	player TEXT NOT NULL,
+	category TEXT NOT NULL,
+	spirit TEXT NOT NULL,
+	group_name TEXT NOT NULL,
+	name TEXT NOT NULL,
+	gender TEXT NOT NULL,
+	embrace_year INTEGER NOT NULL,
+	setting_year INTEGER NOT NULL,
+	description TEXT NOT NULL,
+	notes TEXT NOT NULL,
+	refresh INTEGER NOT NULL,
+	fate_point INTEGER NOT NULL,
+	blood_potency INTEGER NOT NULL,
+	physical_stress_limit INTEGER NOT NULL,
+	physical_stress_current INTEGER NOT NULL,
+	mental_stress_limit INTEGER NOT NULL,
+	mental_stress_current INTEGER NOT NULL,
+	hunger_stress_limit INTEGER NOT NULL,
+	hunger_stress_current INTEGER NOT NULL,
+	revision INTEGER NOT NULL,
+	modified TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS characters_spirit ON characters (spirit);
+CREATE INDEX IF NOT EXISTS characters_player ON characters (player);
+CREATE INDEX IF NOT EXISTS characters_group ON characters (group_name);
+CREATE INDEX IF NOT EXISTS characters_name ON characters (name);
+
+CREATE TABLE IF NOT EXISTS character_aliases (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	alias TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE TABLE IF NOT EXISTS character_tags (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE INDEX IF NOT EXISTS character_tags_tag ON character_tags (tag);
+CREATE TABLE IF NOT EXISTS character_collectives (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	collective TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE INDEX IF NOT EXISTS character_collectives_collective ON character_collectives (collective);
+CREATE TABLE IF NOT EXISTS character_aspects (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	type TEXT NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE TABLE IF NOT EXISTS character_skills (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	group_name TEXT NOT NULL,
+	rating INTEGER NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE TABLE IF NOT EXISTS character_stunts (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE TABLE IF NOT EXISTS character_disciplines (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	rating INTEGER NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+CREATE TABLE IF NOT EXISTS character_consequences (
+	character_id TEXT NOT NULL REFERENCES characters (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	level INTEGER NOT NULL,
+	is_active INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	PRIMARY KEY (character_id, position)
+);
+`
+
+// sqliteCharacterColumns are the columns of the characters table in the order of characterValues
+const sqliteCharacterColumns = `id, player, category, spirit, group_name, name, gender,
+	embrace_year, setting_year, description, notes, refresh, fate_point, blood_potency,
+	physical_stress_limit, physical_stress_current, mental_stress_limit, mental_stress_current,
+	hunger_stress_limit, hunger_stress_current, revision, modified`
+
+// sqliteList maps one list field of a character to its table
+type sqliteList struct {
+	table   string
+	columns string                              // value columns after character_id and position
+	length  func(c *dfm.Character) int          // number of items in the list
+	values  func(c *dfm.Character, i int) []any // column values of item i
+	// scan scans the value columns of an item and returns a function appending it to a character
+	scan func(scan func(dest ...any) error) (func(c *dfm.Character), error)
+}
+
+// sqliteLists are the list fields of a character stored in their own tables
+var sqliteLists = []sqliteList{
+	{
+		table: "character_aliases", columns: "alias",
+		length: func(c *dfm.Character) int { return len(c.Aliases) },
+		values: func(c *dfm.Character, i int) []any { return []any{c.Aliases[i]} },
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var alias string
+			err := scan(&alias)
+			return func(c *dfm.Character) { c.Aliases = append(c.Aliases, alias) }, err
+		},
+	},
+	{
+		table: "character_tags", columns: "tag",
+		length: func(c *dfm.Character) int { return len(c.Tags) },
+		values: func(c *dfm.Character, i int) []any { return []any{c.Tags[i]} },
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var tag string
+			err := scan(&tag)
+			return func(c *dfm.Character) { c.Tags = append(c.Tags, tag) }, err
+		},
+	},
+	{
+		table: "character_collectives", columns: "collective",
+		length: func(c *dfm.Character) int { return len(c.Collectives) },
+		values: func(c *dfm.Character, i int) []any { return []any{c.Collectives[i]} },
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var collective string
+			err := scan(&collective)
+			return func(c *dfm.Character) { c.Collectives = append(c.Collectives, collective) }, err
+		},
+	},
+	{
+		table: "character_aspects", columns: "type, title, description",
+		length: func(c *dfm.Character) int { return len(c.Aspects) },
+		values: func(c *dfm.Character, i int) []any {
+			a := c.Aspects[i]
+			return []any{a.Type, a.Title, a.Description}
+		},
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var a dfm.Aspect
+			err := scan(&a.Type, &a.Title, &a.Description)
+			return func(c *dfm.Character) { c.Aspects = append(c.Aspects, a) }, err
+		},
+	},
+	{
+		table: "character_skills", columns: "title, group_name, rating",
+		length: func(c *dfm.Character) int { return len(c.Skills) },
+		values: func(c *dfm.Character, i int) []any {
+			s := c.Skills[i]
+			return []any{s.Title, s.Group, s.Rating}
+		},
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var s dfm.Skill
+			err := scan(&s.Title, &s.Group, &s.Rating)
+			return func(c *dfm.Character) { c.Skills = append(c.Skills, s) }, err
+		},
+	},
+	{
+		table: "character_stunts", columns: "title, description",
+		length: func(c *dfm.Character) int { return len(c.Stunts) },
+		values: func(c *dfm.Character, i int) []any {
+			s := c.Stunts[i]
+			return []any{s.Title, s.Description}
+		},
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var s dfm.Stunt
+			err := scan(&s.Title, &s.Description)
+			return func(c *dfm.Character) { c.Stunts = append(c.Stunts, s) }, err
+		},
+	},
+	{
+		table: "character_disciplines", columns: "title, rating",
+		length: func(c *dfm.Character) int { return len(c.Disciplines) },
+		values: func(c *dfm.Character, i int) []any {
+			d := c.Disciplines[i]
+			return []any{d.Title, d.Rating}
+		},
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var d dfm.Discipline
+			err := scan(&d.Title, &d.Rating)
+			return func(c *dfm.Character) { c.Disciplines = append(c.Disciplines, d) }, err
+		},
+	},
+	{
+		table: "character_consequences", columns: "level, is_active, title",
+		length: func(c *dfm.Character) int { return len(c.Consequences) },
+		values: func(c *dfm.Character, i int) []any {
+			cq := c.Consequences[i]
+			return []any{cq.Level, cq.IsActive, cq.Title}
+		},
+		scan: func(scan func(dest ...any) error) (func(c *dfm.Character), error) {
+			var cq dfm.Consequence
+			err := scan(&cq.Level, &cq.IsActive, &cq.Title)
+			return func(c *dfm.Character) { c.Consequences = append(c.Consequences, cq) }, err
+		},
+	},
+}
+
+// sqlQuerier runs queries on a database or in a transaction
+type sqlQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// SqliteProvider implements the Provider interface using an embedded SQLite database.
+// Lists of a character, such as aspects and skills, are stored in their own tables
+// and every change is made in a transaction. The character rows and their lists are read
+// in one transaction too, so a read never mixes two revisions of a character.
+// Empty lists are read back as empty lists, not nil.
+type SqliteProvider struct {
+	mu sync.Mutex // makes changes one at a time, so change events are sent in order
+	db *sql.DB
+
+	changes changeFeed // subscribers to character changes
+}
+
+// NewSqliteProvider opens a SQLite character database, creating the file and its tables if needed.
+// Call Close to close the database.
+func NewSqliteProvider(path string) (*SqliteProvider, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	// Foreign keys remove the lists of deleted characters, the busy timeout lets other
+	// processes finish their transactions
+	dsn := (&url.URL{
+		Scheme:   "file",
+		Opaque:   path,
+		RawQuery: "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)",
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	// A single connection serializes transactions, SQLite allows only one writer anyway
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables in %s: %w", path, err)
+	}
+
+	return &SqliteProvider{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SqliteProvider) Close() error {
+	return s.db.Close()
+}
+
+// Create stores a new character with its lists. New characters start at revision 0,
+// restored ones keep the revision they are given.
+func (s *SqliteProvider) Create(character dfm.Character) error {
+	if err := validateCharacterName(character.Name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	character.Modified = time.Now().UTC()
+	character.EmptyLists()
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM characters WHERE id = ?", character.ID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to query characters: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %s", ErrCharacterExists, character.ID)
+		}
+		return insertCharacter(tx, character)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.changes.notify(ChangeEvent{Type: ChangeCreated, CharacterID: character.ID, Character: character})
+	return nil
+}
+
+// Read retrieves a character by ID.
+func (s *SqliteProvider) Read(characterID string) (dfm.Character, error) {
+	var character dfm.Character
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		character, err = readCharacter(tx, characterID)
+		return err
+	})
+	return character, err
+}
+
+// Update replaces an existing character and its lists if it is still at expectedRevision.
+func (s *SqliteProvider) Update(character dfm.Character, expectedRevision int) error {
+	if err := validateCharacterName(character.Name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		stored, err := readCharacter(tx, character.ID)
+		if err != nil {
+			return err
+		}
+
+		// Reject updates based on an old revision
+		if stored.Revision != expectedRevision {
+			return &StaleRevisionError{ExpectedRevision: expectedRevision, Current: stored}
+		}
+		character.Revision = stored.Revision + 1
+		character.Modified = time.Now().UTC()
+		character.EmptyLists()
+
+		// The lists are removed with the old row and inserted again
+		if _, err := tx.Exec("DELETE FROM characters WHERE id = ?", character.ID); err != nil {
+			return fmt.Errorf("failed to replace character: %w", err)
+		}
+		return insertCharacter(tx, character)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.changes.notify(ChangeEvent{Type: ChangeUpdated, CharacterID: character.ID, Character: character})
+	return nil
+}
+
+// Delete removes a character and its lists by ID.
+func (s *SqliteProvider) Delete(characterID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed dfm.Character
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		if removed, err = readCharacter(tx, characterID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM characters WHERE id = ?", characterID); err != nil {
+			return fmt.Errorf("failed to delete character: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.changes.notify(ChangeEvent{Type: ChangeDeleted, CharacterID: characterID, Character: removed})
+	return nil
+}
+
+// List returns characters matching the query, sorted and paginated (see dfm.CharacterQuery.Apply).
+// When all filters must match, the spirit, player and group filters narrow the rows read from the database.
+func (s *SqliteProvider) List(query dfm.CharacterQuery) ([]dfm.Character, error) {
+	var conditions []string
+	var args []any
+	if query.Match != dfm.MatchAny {
+		for column, value := range map[string]string{"spirit": query.Spirit, "player": query.Player, "group_name": query.Group} {
+			if value != "" {
+				conditions = append(conditions, column+" = ?")
+				args = append(args, value)
+			}
+		}
+	}
+
+	where := "1"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+	var characters []dfm.Character
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		characters, err = loadCharacters(tx, where, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(characters), nil
+}
+
+// Subscribe returns a channel receiving the characters created, updated and deleted
+// through the provider, and a function that ends the subscription.
+func (s *SqliteProvider) Subscribe() (<-chan ChangeEvent, func()) {
+	return s.changes.Subscribe()
+}
+
+// inTransaction runs fn in a transaction, committing it if fn succeeds.
+func (s *SqliteProvider) inTransaction(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertCharacter inserts a character row and the rows of its lists.
+func insertCharacter(tx *sql.Tx, character dfm.Character) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(characterValues(&character))), ", ")
+	_, err := tx.Exec("INSERT INTO characters ("+sqliteCharacterColumns+") VALUES ("+placeholders+")", characterValues(&character)...)
+	if err != nil {
+		return fmt.Errorf("failed to insert character %s: %w", character.ID, err)
+	}
+
+	for _, list := range sqliteLists {
+		if list.length(&character) == 0 {
+			continue
+		}
+		columns := strings.Count(list.columns, ",") + 1
+		statement, err := tx.Prepare("INSERT INTO " + list.table + " (character_id, position, " + list.columns +
+			") VALUES (?, ?" + strings.Repeat(", ?", columns) + ")")
+		if err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", list.table, err)
+		}
+		for i := range list.length(&character) {
+			if _, err := statement.Exec(append([]any{character.ID, i}, list.values(&character, i)...)...); err != nil {
+				statement.Close()
+				return fmt.Errorf("failed to insert into %s: %w", list.table, err)
+			}
+		}
+		statement.Close()
+	}
+	return nil
+}
+
+// characterValues returns pointers to the fields of a character in the order of sqliteCharacterColumns,
+// for both inserting and scanning.
+func characterValues(c *dfm.Character) []any {
+	return []any{&c.ID, &c.Player, &c.Category, &c.Spirit, &c.Group, &c.Name, &c.Gender,
+		&c.EmbraceYear, &c.SettingYear, &c.Description, &c.Notes, &c.Refresh, &c.FatePoint, &c.BloodPotency,
+		&c.PhysicalStressLimit, &c.PhysicalStressCurrent, &c.MentalStressLimit, &c.MentalStressCurrent,
+		&c.HungerStressLimit, &c.HungerStressCurrent, &c.Revision, sqliteTime{&c.Modified}}
+}
+
+// sqliteTime stores a time as RFC 3339 text, and the zero time as empty text
+type sqliteTime struct{ t *time.Time }
+
+// Value implements driver.Valuer.
+func (s sqliteTime) Value() (driver.Value, error) {
+	if s.t.IsZero() {
+		return "", nil
+	}
+	return s.t.UTC().Format(time.RFC3339Nano), nil
+}
+
+// Scan implements sql.Scanner.
+func (s sqliteTime) Scan(value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected text for time, got %T", value)
+	}
+	if text == "" {
+		*s.t = time.Time{}
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return err
+	}
+	*s.t = t
+	return nil
+}
+
+// readCharacter reads a character by ID, returning ErrCharacterNotFound if it does not exist.
+func readCharacter(q sqlQuerier, characterID string) (dfm.Character, error) {
+	characters, err := loadCharacters(q, "id = ?", characterID)
+	if err != nil {
+		return dfm.Character{}, err
+	}
+	if len(characters) == 0 {
+		return dfm.Character{}, ErrCharacterNotFound
+	}
+	return characters[0], nil
+}
+
+// loadCharacters loads the characters matching an SQL condition on the characters table,
+// ordered by name. The lists are loaded with one query per list table.
+func loadCharacters(q sqlQuerier, where string, args ...any) ([]dfm.Character, error) {
+	rows, err := q.Query("SELECT "+sqliteCharacterColumns+" FROM characters WHERE "+where+" ORDER BY name, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query characters: %w", err)
+	}
+	var characters []dfm.Character
+	for rows.Next() {
+		var character dfm.Character
+		if err := rows.Scan(characterValues(&character)...); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read character: %w", err)
+		}
+		// Lists without rows are empty, the same as in the other providers
+		character.EmptyLists()
+		characters = append(characters, character)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query characters: %w", err)
+	}
+	if len(characters) == 0 {
+		return characters, nil
+	}
+
+	index := make(map[string]int, len(characters))
+	for i, character := range characters {
+		index[character.ID] = i
+	}
+	for _, list := range sqliteLists {
+		if err := loadList(q, list, characters, index, where, args); err != nil {
+			return nil, err
+		}
+	}
+	return characters, nil
+}
+
+// loadList loads the items of one list of the characters matching the condition.
+func loadList(q sqlQuerier, list sqliteList, characters []dfm.Character, index map[string]int, where string, args []any) error {
+	rows, err := q.Query("SELECT character_id, "+list.columns+" FROM "+list.table+
+		" WHERE character_id IN (SELECT id FROM characters WHERE "+where+") ORDER BY character_id, position", args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", list.table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var characterID string
+		appendItem, err := list.scan(func(dest ...any) error {
+			return rows.Scan(append([]any{&characterID}, dest...)...)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", list.table, err)
+		}
+		if i, ok := index[characterID]; ok {
+			appendItem(&characters[i])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query %s: %w", list.table, err)
+	}
+	return nil
+}
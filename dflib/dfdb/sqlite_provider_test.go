@@ -0,0 +1,187 @@
+package dfdb
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// newTestSqliteProvider opens a SQLite provider on a database in a temporary directory
+func newTestSqliteProvider(t *testing.T) *SqliteProvider {
+	t.Helper()
+	provider, err := NewSqliteProvider(filepath.Join(t.TempDir(), "db", "characters.db"))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	t.Cleanup(func() { provider.Close() })
+	return provider
+}
+
+// countRows counts the rows of a table
+func countRows(t *testing.T, provider *SqliteProvider, table string) int {
+	t.Helper()
+	var count int
+	if err := provider.db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rows of %s: %v", table, err)
+	}
+	return count
+}
+
+func TestSqliteStoresLists(t *testing.T) {
+	provider := newTestSqliteProvider(t)
+
+	char := dfm.NewCharacter(dfm.SpiritVampire)
+	char.Name = "Lucius"
+	char.Aliases = []string{"The Red", "Old Man"}
+	char.Tags = []string{"elder"}
+	char.Collectives = []string{"Camarilla"}
+	char.Aspects[0].Title = "Ancient Roman Noble"
+	char.Aspects[0].Description = "Remembers the Republic"
+	char.Skills[2].Rating = 4
+	char.Stunts = []dfm.Stunt{{Title: "Commanding", Description: "+2 to provoke"}}
+	char.Disciplines[0].Rating = 3
+	char.Consequences[0] = dfm.Consequence{Level: 2, IsActive: true, Title: "Singed"}
+
+	if err := provider.Create(char); err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	read, err := provider.Read(char.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if changes := dfm.Diff(char, read); len(changes) != 0 {
+		t.Errorf("Character changed when stored: %+v", changes)
+	}
+
+	// Lists are replaced on update, also when they get shorter
+	char.Aliases = char.Aliases[:1]
+	char.Stunts = nil
+	if err := provider.Update(char, 0); err != nil {
+		t.Fatalf("Failed to update character: %v", err)
+	}
+	read, _ = provider.Read(char.ID)
+	if changes := dfm.Diff(char, read); len(changes) != 0 {
+		t.Errorf("Character changed when updated: %+v", changes)
+	}
+	if count := countRows(t, provider, "character_aliases"); count != 1 {
+		t.Errorf("Expected 1 alias row, got %d", count)
+	}
+}
+
+func TestSqliteDeleteRemovesLists(t *testing.T) {
+	provider := newTestSqliteProvider(t)
+
+	char := dfm.NewCharacter(dfm.SpiritHuman)
+	char.Name = "To Delete"
+	char.Tags = []string{"doomed"}
+	provider.Create(char)
+
+	if err := provider.Delete(char.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+
+	for _, list := range sqliteLists {
+		if count := countRows(t, provider, list.table); count != 0 {
+			t.Errorf("Expected no rows left in %s, got %d", list.table, count)
+		}
+	}
+	if err := provider.Delete(char.ID); err != ErrCharacterNotFound {
+		t.Errorf("Expected ErrCharacterNotFound, got %v", err)
+	}
+}
+
+func TestSqliteCreateExistingFails(t *testing.T) {
+	provider := newTestSqliteProvider(t)
+
+	char := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Original", Spirit: "human"}
+	provider.Create(char)
+
+	char.Name = "Duplicate"
+	if err := provider.Create(char); err == nil {
+		t.Fatal("Expected error creating a character with an existing ID")
+	}
+
+	// The failed transaction leaves the stored character unchanged
+	if read, _ := provider.Read(char.ID); read.Name != "Original" {
+		t.Errorf("Expected Original, got %s", read.Name)
+	}
+}
+
+func TestSqliteListOrderedByName(t *testing.T) {
+	provider := newTestSqliteProvider(t)
+
+	for _, c := range []dfm.Character{
+		{ID: "id-1", Name: "Charlie", Spirit: "human"},
+		{ID: "id-2", Name: "Alice", Spirit: "human"},
+		{ID: "id-3", Name: "Bob", Spirit: "vampire"},
+	} {
+		provider.Create(c)
+	}
+
+	result, err := provider.List(dfm.CharacterQuery{})
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	var names []string
+	for _, c := range result {
+		names = append(names, c.Name)
+	}
+	if len(names) != 3 || names[0] != "Alice" || names[1] != "Bob" || names[2] != "Charlie" {
+		t.Errorf("Expected characters ordered by name, got %v", names)
+	}
+}
+
+func TestSqliteReadsDoNotMixRevisions(t *testing.T) {
+	provider := newTestSqliteProvider(t)
+	char := dfm.Character{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Lucius", Aliases: []string{"rev 0"}}
+	if err := provider.Create(char); err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+
+	// Every revision has an alias naming it, so a read mixing the character row of
+	// one revision with the lists of another has the wrong alias
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for revision := range 50 {
+			char.Aliases = []string{fmt.Sprintf("rev %d", revision+1)}
+			if err := provider.Update(char, revision); err != nil {
+				t.Errorf("Failed to update character: %v", err)
+				return
+			}
+		}
+	}()
+
+	// Stop reading at the first failure, but let the updates finish before the test ends
+	check := func(c dfm.Character) bool {
+		if want := fmt.Sprintf("rev %d", c.Revision); len(c.Aliases) != 1 || c.Aliases[0] != want {
+			t.Errorf("Read revision %d with aliases %v, want [%s]", c.Revision, c.Aliases, want)
+			return false
+		}
+		return true
+	}
+	defer func() { <-done }()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		read, err := provider.Read(char.ID)
+		if err != nil {
+			t.Errorf("Failed to read character: %v", err)
+			return
+		}
+		characters, err := provider.List(dfm.CharacterQuery{})
+		if err != nil || len(characters) != 1 {
+			t.Errorf("Failed to list characters: %v, %v", characters, err)
+			return
+		}
+		if !check(read) || !check(characters[0]) {
+			return
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package dfdb
+
+import (
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// TrackerProvider defines the interface for session Fate tracker log storage backends.
+type TrackerProvider interface {
+	// Append adds entries to the end of a session's tracker log, creating the log if needed.
+	Append(sessionID string, entries ...dfm.TrackerEntry) error
+	// Read retrieves the tracker log of a session, returning an error if not found.
+	Read(sessionID string) (dfm.SessionTracker, error)
+	// List returns the IDs of all sessions that have a tracker log.
+	List() ([]string, error)
+}
@@ -0,0 +1,13 @@
+package dfdb
+
+import (
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// UserProvider defines the interface for user registry backends.
+type UserProvider interface {
+	// ReadUser retrieves a user by username, returning an error if not found.
+	ReadUser(username string) (dfm.User, error)
+	// ListUsers returns all registered users, sorted by username.
+	ListUsers() ([]dfm.User, error)
+}
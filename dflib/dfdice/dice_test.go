@@ -0,0 +1,134 @@
+package dfdice
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRollFacesInRange(t *testing.T) {
+	roller := NewRoller(rand.NewPCG(1, 2))
+
+	for i := 0; i < 1000; i++ {
+		result := roller.Roll("", 0)
+		for _, face := range result.Dice {
+			if face < Minus || face > Plus {
+				t.Fatalf("Face out of range: %d", face)
+			}
+		}
+		if total := result.DiceTotal(); total < -4 || total > 4 {
+			t.Fatalf("Dice total out of range: %d", total)
+		}
+	}
+}
+
+func TestRollAllFacesAppear(t *testing.T) {
+	roller := NewRoller(rand.NewPCG(3, 4))
+
+	seen := make(map[Face]bool)
+	for i := 0; i < 100; i++ {
+		for _, face := range roller.Roll("", 0).Dice {
+			seen[face] = true
+		}
+	}
+
+	for _, face := range []Face{Minus, Blank, Plus} {
+		if !seen[face] {
+			t.Errorf("Face %q never rolled", face.String())
+		}
+	}
+}
+
+func TestRollDeterministicWithSameSeed(t *testing.T) {
+	first := NewRoller(rand.NewPCG(42, 42))
+	second := NewRoller(rand.NewPCG(42, 42))
+
+	for i := 0; i < 20; i++ {
+		a := first.Roll("fight", 2)
+		b := second.Roll("fight", 2)
+		if a.Dice != b.Dice {
+			t.Fatalf("Roll %d differs with same seed: %v vs %v", i, a.Dice, b.Dice)
+		}
+	}
+}
+
+func TestRollKeepsSkillAndBonus(t *testing.T) {
+	roller := NewRoller(nil)
+
+	result := roller.Roll("athletics", 3)
+	if result.Skill != "athletics" {
+		t.Errorf("Skill mismatch: got %s, want athletics", result.Skill)
+	}
+	if result.Bonus != 3 {
+		t.Errorf("Bonus mismatch: got %d, want 3", result.Bonus)
+	}
+	if result.Total() != result.DiceTotal()+3 {
+		t.Errorf("Total mismatch: got %d, want %d", result.Total(), result.DiceTotal()+3)
+	}
+	if result.RolledAt.IsZero() {
+		t.Error("RolledAt should be set")
+	}
+}
+
+func TestResultTotalAndLadder(t *testing.T) {
+	tests := []struct {
+		name     string
+		dice     [DiceCount]Face
+		bonus    int
+		modifier int
+		total    int
+		ladder   string
+	}{
+		{"All blank", [DiceCount]Face{Blank, Blank, Blank, Blank}, 0, 0, 0, "Mediocre"},
+		{"All plus", [DiceCount]Face{Plus, Plus, Plus, Plus}, 4, 0, 8, "Legendary"},
+		{"All minus", [DiceCount]Face{Minus, Minus, Minus, Minus}, 0, 0, -4, "Terrible"},
+		{"Mixed with bonus", [DiceCount]Face{Plus, Minus, Blank, Plus}, 2, 0, 3, "Good"},
+		{"Beyond legendary", [DiceCount]Face{Plus, Plus, Plus, Plus}, 6, 0, 10, "Legendary"},
+		{"With modifier", [DiceCount]Face{Blank, Blank, Plus, Plus}, 1, 2, 5, "Superb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Result{Dice: tt.dice, Bonus: tt.bonus, Modifier: tt.modifier}
+			if result.Total() != tt.total {
+				t.Errorf("Total = %d, want %d", result.Total(), tt.total)
+			}
+			if result.Ladder() != tt.ladder {
+				t.Errorf("Ladder = %s, want %s", result.Ladder(), tt.ladder)
+			}
+		})
+	}
+}
+
+func TestFacesString(t *testing.T) {
+	result := Result{Dice: [DiceCount]Face{Plus, Minus, Blank, Plus}}
+	if got := result.FacesString(); got != "[+][-][ ][+]" {
+		t.Errorf("FacesString = %q, want %q", got, "[+][-][ ][+]")
+	}
+}
+
+func TestLadderName(t *testing.T) {
+	tests := []struct {
+		value int
+		name  string
+	}{
+		{-3, "Terrible"},
+		{-2, "Terrible"},
+		{-1, "Poor"},
+		{0, "Mediocre"},
+		{1, "Average"},
+		{2, "Fair"},
+		{3, "Good"},
+		{4, "Great"},
+		{5, "Superb"},
+		{6, "Fantastic"},
+		{7, "Epic"},
+		{8, "Legendary"},
+		{9, "Legendary"},
+	}
+
+	for _, tt := range tests {
+		if got := LadderName(tt.value); got != tt.name {
+			t.Errorf("LadderName(%d) = %s, want %s", tt.value, got, tt.name)
+		}
+	}
+}
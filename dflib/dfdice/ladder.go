@@ -0,0 +1,33 @@
+package dfdice
+
+// ladder maps Fate ladder values to their names, from -2 (Terrible) to +8 (Legendary)
+var ladder = map[int]string{
+	-2: "Terrible",
+	-1: "Poor",
+	0:  "Mediocre",
+	1:  "Average",
+	2:  "Fair",
+	3:  "Good",
+	4:  "Great",
+	5:  "Superb",
+	6:  "Fantastic",
+	7:  "Epic",
+	8:  "Legendary",
+}
+
+const (
+	ladderMin = -2
+	ladderMax = 8
+)
+
+// LadderName returns the Fate ladder name for a value.
+// Values beyond the ends of the ladder are clamped to Terrible or Legendary.
+func LadderName(value int) string {
+	if value < ladderMin {
+		value = ladderMin
+	}
+	if value > ladderMax {
+		value = ladderMax
+	}
+	return ladder[value]
+}
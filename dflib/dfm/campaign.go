@@ -0,0 +1,36 @@
+package dfm
+
+import (
+	"slices"
+	"time"
+)
+
+// Campaign represents a Dark Fate campaign set in a chronicle.
+// Campaigns are played in sessions.
+type Campaign struct {
+	// ID is the unique identifier (UUID v4)
+	ID string `json:"id" yaml:"id"`
+	// ChronicleID is the ID of the chronicle the campaign belongs to
+	ChronicleID string `json:"chronicleId" yaml:"chronicleId"`
+	// Name is the display name of the campaign
+	Name string `json:"name" yaml:"name"`
+	// Description is a short description of the campaign
+	Description string `json:"description" yaml:"description"`
+	// Gamemaster is the username of the gamemaster running the campaign
+	Gamemaster string `json:"gamemaster" yaml:"gamemaster"`
+	// Players is the list of usernames taking part in the campaign
+	Players []string `json:"players" yaml:"players"`
+	// Characters is the list of IDs of the PCs and NPCs in the campaign
+	Characters []string `json:"characters" yaml:"characters"`
+	// Revealed is the list of IDs of the NPCs the gamemaster has revealed to the players
+	Revealed []string `json:"revealed" yaml:"revealed"`
+	// CreatedAt is when the campaign was created
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// UpdatedAt is when the campaign was last modified
+	UpdatedAt time.Time `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// IsMember reports whether the user is the gamemaster or one of the players of the campaign
+func (c Campaign) IsMember(username string) bool {
+	return username == c.Gamemaster || slices.Contains(c.Players, username)
+}
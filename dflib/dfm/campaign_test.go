@@ -0,0 +1,20 @@
+package dfm
+
+import "testing"
+
+func TestIsMember(t *testing.T) {
+	chronicle := Chronicle{Gamemaster: "gm", Players: []string{"alice"}}
+	campaign := Campaign{Gamemaster: "gm", Players: []string{"alice"}}
+	session := Session{Gamemaster: "gm", Players: []string{"alice"}}
+
+	for _, username := range []string{"gm", "alice"} {
+		if !chronicle.IsMember(username) || !campaign.IsMember(username) || !session.IsMember(username) {
+			t.Errorf("%s should be a member", username)
+		}
+	}
+	for _, username := range []string{"bob", ""} {
+		if chronicle.IsMember(username) || campaign.IsMember(username) || session.IsMember(username) {
+			t.Errorf("%q should not be a member", username)
+		}
+	}
+}
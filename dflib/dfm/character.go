@@ -1,5 +1,10 @@
 package dfm
 
+import (
+	"strings"
+	"time"
+)
+
 // Character represents a complete Dark Fate RPG character.
 // Supports vampire, ghoul, and human spirit types.
 type Character struct {
@@ -59,6 +64,75 @@ type Character struct {
 	HungerStressLimit int `json:"hungerStressLimit,omitempty" yaml:"hungerStressLimit,omitempty"`
 	// HungerStressCurrent is the number of hunger stress slots used (vampire spirit only)
 	HungerStressCurrent int `json:"hungerStressCurrent,omitempty" yaml:"hungerStressCurrent,omitempty"`
+	// Revision is the number of times the character has been updated, used to detect conflicting edits
+	Revision int `json:"revision" yaml:"revision"`
+	// Modified is the time of the last change, set when the character is stored
+	Modified time.Time `json:"modified,omitzero" yaml:"modified,omitempty"`
+}
+
+// Clone returns a deep copy of the character that shares no slices with the original.
+func (c Character) Clone() Character {
+	clone := c
+	clone.Aliases = cloneSlice(c.Aliases)
+	clone.Tags = cloneSlice(c.Tags)
+	clone.Collectives = cloneSlice(c.Collectives)
+	clone.Aspects = cloneSlice(c.Aspects)
+	clone.Skills = cloneSlice(c.Skills)
+	clone.Stunts = cloneSlice(c.Stunts)
+	clone.Disciplines = cloneSlice(c.Disciplines)
+	clone.Consequences = cloneSlice(c.Consequences)
+	return clone
+}
+
+// cloneSlice copies a slice, keeping nil slices nil so JSON output is unchanged
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// EmptyLists replaces nil lists with empty ones. The dfdb providers store and return
+// characters with empty lists, so a character reads the same from every provider.
+func (c *Character) EmptyLists() {
+	c.Aliases = emptyIfNil(c.Aliases)
+	c.Tags = emptyIfNil(c.Tags)
+	c.Collectives = emptyIfNil(c.Collectives)
+	c.Aspects = emptyIfNil(c.Aspects)
+	c.Skills = emptyIfNil(c.Skills)
+	c.Stunts = emptyIfNil(c.Stunts)
+	c.Disciplines = emptyIfNil(c.Disciplines)
+	c.Consequences = emptyIfNil(c.Consequences)
+}
+
+// emptyIfNil returns an empty slice for a nil slice
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// DropBlankEntries removes blank aliases, tags and collectives, such as the [""]
+// placeholders in the character templates in docs/. Validate rejects blank entries.
+func (c *Character) DropBlankEntries() {
+	c.Aliases = dropBlank(c.Aliases)
+	c.Tags = dropBlank(c.Tags)
+	c.Collectives = dropBlank(c.Collectives)
+}
+
+// dropBlank returns a copy of the values without blank ones, keeping nil slices nil
+func dropBlank(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	kept := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
 }
 
 // CharacterType represents whether a character is a PC or NPC
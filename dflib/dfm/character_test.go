@@ -2,6 +2,7 @@ package dfm
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -186,3 +187,23 @@ func TestSpiritTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestDropBlankEntries(t *testing.T) {
+	char := Character{
+		Aliases:     []string{"", "The Red", " "},
+		Tags:        []string{""},
+		Collectives: nil,
+	}
+	aliases := char.Aliases
+
+	char.DropBlankEntries()
+	if fmt.Sprintf("%q %q", char.Aliases, char.Tags) != `["The Red"] []` || char.Collectives != nil {
+		t.Errorf("Unexpected entries after dropping blank ones: %q %q %q", char.Aliases, char.Tags, char.Collectives)
+	}
+	if aliases[0] != "" {
+		t.Error("DropBlankEntries changed the original list")
+	}
+	if err := char.Validate(); err != nil {
+		t.Errorf("Expected a valid character, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package dfm
+
+import (
+	"slices"
+	"time"
+)
+
+// Chronicle represents a Dark Fate story setting.
+// A chronicle includes playable campaigns for specific characters.
+type Chronicle struct {
+	// ID is the unique identifier (UUID v4)
+	ID string `json:"id" yaml:"id"`
+	// Name is the display name of the chronicle
+	Name string `json:"name" yaml:"name"`
+	// Description is a short description of the chronicle
+	Description string `json:"description" yaml:"description"`
+	// Gamemaster is the username of the gamemaster running the chronicle
+	Gamemaster string `json:"gamemaster" yaml:"gamemaster"`
+	// Players is the list of usernames taking part in the chronicle
+	Players []string `json:"players" yaml:"players"`
+	// Characters is the list of IDs of the characters in the chronicle
+	Characters []string `json:"characters" yaml:"characters"`
+	// CreatedAt is when the chronicle was created
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// UpdatedAt is when the chronicle was last modified
+	UpdatedAt time.Time `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// IsMember reports whether the user is the gamemaster or one of the players of the chronicle
+func (c Chronicle) IsMember(username string) bool {
+	return username == c.Gamemaster || slices.Contains(c.Players, username)
+}
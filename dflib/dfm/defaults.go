@@ -0,0 +1,122 @@
+package dfm
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// Default values of a new character, see docs/characters_json_format.md
+const (
+	// DefaultYear is the default embrace and setting year
+	DefaultYear = 1982
+	// DefaultRefresh is the default fate point refresh
+	DefaultRefresh = 3
+	// DefaultStressLimit is the default number of slots in each stress track
+	DefaultStressLimit = 3
+	// DefaultBloodPotency is the default potency of vampire's blood
+	DefaultBloodPotency = 1
+)
+
+// DefaultSkills are the skills of every character, all at rating 0
+var DefaultSkills = []Skill{
+	{Title: "academics", Group: "mental"},
+	{Title: "athletics", Group: "physical"},
+	{Title: "contacts", Group: "social"},
+	{Title: "crafts", Group: "mental"},
+	{Title: "deceive", Group: "social"},
+	{Title: "drive", Group: "physical"},
+	{Title: "empathy", Group: "social"},
+	{Title: "fight", Group: "physical"},
+	{Title: "investigate", Group: "mental"},
+	{Title: "larceny", Group: "physical"},
+	{Title: "lore", Group: "mental"},
+	{Title: "notice", Group: "mental"},
+	{Title: "physique", Group: "physical"},
+	{Title: "provoke", Group: "social"},
+	{Title: "rapport", Group: "social"},
+	{Title: "resources", Group: "social"},
+	{Title: "shoot", Group: "physical"},
+	{Title: "stealth", Group: "physical"},
+	{Title: "technology", Group: "mental"},
+	{Title: "will", Group: "social"},
+}
+
+// DefaultDisciplines are the disciplines of every vampire character, all at rating 0
+var DefaultDisciplines = []Discipline{
+	{Title: "animalism"},
+	{Title: "auspex"},
+	{Title: "celerity"},
+	{Title: "dominate"},
+	{Title: "majesty"},
+	{Title: "nightmare"},
+	{Title: "obfuscate"},
+	{Title: "protean"},
+	{Title: "resilience"},
+	{Title: "vigor"},
+	{Title: "coils of the ascendant"},
+	{Title: "coils of the sanguine"},
+	{Title: "coils of the wyrm"},
+	{Title: "coils of the voivode"},
+	{Title: "crúac"},
+	{Title: "theban sorcery"},
+}
+
+// defaultAspectTypes are the aspect types of a new character by spirit
+var defaultAspectTypes = map[SpiritType][]string{
+	SpiritHuman:   {"high concept", "trouble", "relationship", "free"},
+	SpiritVampire: {"high concept", "trouble", "clan", "covenant"},
+	SpiritGhoul:   {"high concept", "trouble", "covenant", "relationship"},
+}
+
+// NewCharacter creates a character with the default values of the given spirit
+// and a new UUID v4. Unknown spirits get the defaults of a human.
+// Vampires also get blood potency, disciplines and a hunger stress track.
+func NewCharacter(spirit SpiritType) Character {
+	aspectTypes, ok := defaultAspectTypes[spirit]
+	if !ok {
+		spirit = SpiritHuman
+		aspectTypes = defaultAspectTypes[spirit]
+	}
+
+	character := Character{
+		ID:                  NewID(),
+		Category:            "character",
+		Spirit:              string(spirit),
+		Group:               string(NPC),
+		Gender:              "male",
+		Aliases:             []string{},
+		Tags:                []string{},
+		Collectives:         []string{},
+		EmbraceYear:         DefaultYear,
+		SettingYear:         DefaultYear,
+		Refresh:             DefaultRefresh,
+		Skills:              cloneSlice(DefaultSkills),
+		Stunts:              []Stunt{{}},
+		PhysicalStressLimit: DefaultStressLimit,
+		MentalStressLimit:   DefaultStressLimit,
+		Consequences: []Consequence{
+			{Level: 2},
+			{Level: 4},
+			{Level: 6},
+		},
+	}
+	for _, aspectType := range aspectTypes {
+		character.Aspects = append(character.Aspects, Aspect{Type: aspectType})
+	}
+
+	if spirit == SpiritVampire {
+		character.BloodPotency = DefaultBloodPotency
+		character.Disciplines = cloneSlice(DefaultDisciplines)
+		character.HungerStressLimit = DefaultStressLimit
+	}
+	return character
+}
+
+// NewID returns a random UUID v4 for identifying characters and other records.
+func NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
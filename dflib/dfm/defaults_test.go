@@ -0,0 +1,98 @@
+package dfm
+
+import (
+	"regexp"
+	"testing"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewCharacterDefaults(t *testing.T) {
+	tests := []struct {
+		spirit      SpiritType
+		aspects     []string
+		disciplines int
+		hunger      int
+		potency     int
+	}{
+		{SpiritHuman, []string{"high concept", "trouble", "relationship", "free"}, 0, 0, 0},
+		{SpiritVampire, []string{"high concept", "trouble", "clan", "covenant"}, len(DefaultDisciplines), DefaultStressLimit, DefaultBloodPotency},
+		{SpiritGhoul, []string{"high concept", "trouble", "covenant", "relationship"}, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.spirit), func(t *testing.T) {
+			character := NewCharacter(tt.spirit)
+
+			if err := character.Validate(); err != nil {
+				t.Errorf("Expected a valid default character, got %v", err)
+			}
+			if !uuidV4Pattern.MatchString(character.ID) {
+				t.Errorf("Expected a UUID v4 ID, got %q", character.ID)
+			}
+			if character.Spirit != string(tt.spirit) || character.Category != "character" {
+				t.Errorf("Unexpected spirit or category: %q %q", character.Spirit, character.Category)
+			}
+			if character.Refresh != DefaultRefresh || character.PhysicalStressLimit != DefaultStressLimit || character.MentalStressLimit != DefaultStressLimit {
+				t.Errorf("Unexpected refresh or stress limits: %+v", character)
+			}
+
+			if len(character.Aspects) != len(tt.aspects) {
+				t.Fatalf("Expected %d aspects, got %d", len(tt.aspects), len(character.Aspects))
+			}
+			for i, aspectType := range tt.aspects {
+				if character.Aspects[i].Type != aspectType {
+					t.Errorf("Expected aspect %d to be %q, got %q", i, aspectType, character.Aspects[i].Type)
+				}
+			}
+
+			if len(character.Skills) != len(DefaultSkills) {
+				t.Errorf("Expected %d skills, got %d", len(DefaultSkills), len(character.Skills))
+			}
+			for _, skill := range character.Skills {
+				if skill.Rating != 0 {
+					t.Errorf("Expected skill %s at 0, got %d", skill.Title, skill.Rating)
+				}
+			}
+
+			levels := []int{2, 4, 6}
+			if len(character.Consequences) != len(levels) {
+				t.Fatalf("Expected %d consequences, got %d", len(levels), len(character.Consequences))
+			}
+			for i, level := range levels {
+				if character.Consequences[i].Level != level || character.Consequences[i].IsActive {
+					t.Errorf("Unexpected consequence %d: %+v", i, character.Consequences[i])
+				}
+			}
+
+			if len(character.Disciplines) != tt.disciplines || character.HungerStressLimit != tt.hunger || character.BloodPotency != tt.potency {
+				t.Errorf("Unexpected vampire fields: %d disciplines, hunger %d, potency %d",
+					len(character.Disciplines), character.HungerStressLimit, character.BloodPotency)
+			}
+		})
+	}
+}
+
+func TestNewCharacterDoesNotShareDefaults(t *testing.T) {
+	character := NewCharacter(SpiritVampire)
+	character.Skills[0].Rating = 3
+	character.Disciplines[0].Rating = 2
+
+	if DefaultSkills[0].Rating != 0 || DefaultDisciplines[0].Rating != 0 {
+		t.Error("Modifying a new character changed the defaults")
+	}
+}
+
+func TestNewIDIsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 100 {
+		id := NewID()
+		if !uuidV4Pattern.MatchString(id) {
+			t.Fatalf("Expected a UUID v4, got %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,74 @@
+package dfm
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldChange describes a character field that differs between two versions.
+type FieldChange struct {
+	// Field is the JSON name of the field, with an index for list items (e.g. "skills[3].rating")
+	Field string `json:"field" yaml:"field"`
+	// Old is the JSON value in the first version, empty if the list item did not exist
+	Old string `json:"old" yaml:"old"`
+	// New is the JSON value in the second version, empty if the list item was removed
+	New string `json:"new" yaml:"new"`
+}
+
+// Diff returns the fields that differ between two versions of a character, in the
+// order they appear in the character JSON. Items of lists are compared by index.
+// The revision and the modification time are not compared, they change with every update.
+func Diff(old, new Character) []FieldChange {
+	old.Revision, new.Revision = 0, 0
+	old.Modified, new.Modified = time.Time{}, time.Time{}
+	var changes []FieldChange
+	diffValues("", reflect.ValueOf(old), reflect.ValueOf(new), &changes)
+	return changes
+}
+
+// diffValues appends the differences between two values of the same type to changes
+func diffValues(path string, a, b reflect.Value, changes *[]FieldChange) {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			diffValues(name, a.Field(i), b.Field(i), changes)
+		}
+
+	case reflect.Slice:
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			item := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= a.Len():
+				*changes = append(*changes, FieldChange{Field: item, New: jsonValue(b.Index(i))})
+			case i >= b.Len():
+				*changes = append(*changes, FieldChange{Field: item, Old: jsonValue(a.Index(i))})
+			default:
+				diffValues(item, a.Index(i), b.Index(i), changes)
+			}
+		}
+
+	default:
+		if !a.Equal(b) {
+			*changes = append(*changes, FieldChange{Field: path, Old: jsonValue(a), New: jsonValue(b)})
+		}
+	}
+}
+
+// jsonValue returns a value as compact JSON
+func jsonValue(v reflect.Value) string {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
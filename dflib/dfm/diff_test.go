@@ -0,0 +1,42 @@
+package dfm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := NewCharacter(SpiritHuman)
+	old.Name = "Old Name"
+	old.Aliases = []string{"Shadow"}
+
+	new := old.Clone()
+	new.Name = "New Name"
+	new.FatePoint = 2
+	new.Skills[1].Rating = 3
+	new.Aliases = nil
+	new.Stunts = append(new.Stunts, Stunt{Title: "Quick"})
+	new.Revision = 4
+
+	want := []FieldChange{
+		{Field: "name", Old: `"Old Name"`, New: `"New Name"`},
+		{Field: "aliases[0]", Old: `"Shadow"`},
+		{Field: "fatePoint", Old: "0", New: "2"},
+		{Field: "skills[1].rating", Old: "0", New: "3"},
+		{Field: "stunts[1]", New: `{"title":"Quick","description":""}`},
+	}
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	old := NewCharacter(SpiritVampire)
+	new := old.Clone()
+	new.Revision = 1
+	new.Tags = nil // Empty and missing lists are the same
+
+	if changes := Diff(old, new); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,37 @@
+package dfm
+
+import "time"
+
+// HistoryEntryType identifies the kind of a character history entry
+type HistoryEntryType string
+
+const (
+	// HistoryCreated is the creation of a character
+	HistoryCreated HistoryEntryType = "create"
+	// HistoryUpdated is a change to a character
+	HistoryUpdated HistoryEntryType = "update"
+	// HistoryDeleted is the deletion of a character
+	HistoryDeleted HistoryEntryType = "delete"
+	// HistoryRestored is the restore of a previous version of a character, also a deleted one
+	HistoryRestored HistoryEntryType = "restore"
+)
+
+// CharacterHistoryEntry is a single change in the append-only history of a character.
+type CharacterHistoryEntry struct {
+	// Sequence is the position of the entry in the character's history, starting at 1
+	Sequence int `json:"sequence" yaml:"sequence"`
+	// CharacterID identifies the changed character
+	CharacterID string `json:"characterId" yaml:"characterId"`
+	// Type is the kind of the change: "create", "update", "delete" or "restore"
+	Type HistoryEntryType `json:"type" yaml:"type"`
+	// Time is when the change was made
+	Time time.Time `json:"time" yaml:"time"`
+	// Username is the user who made the change, empty for changes made outside the application
+	Username string `json:"username" yaml:"username"`
+	// Changes are the fields changed compared to the previous entry, update and restore entries only
+	Changes []FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	// RestoredSequence is the sequence of the entry whose version was restored, restore entries only
+	RestoredSequence int `json:"restoredSequence,omitempty" yaml:"restoredSequence,omitempty"`
+	// Character is the character after the change, or the character as it was deleted
+	Character Character `json:"character" yaml:"character"`
+}
@@ -0,0 +1,127 @@
+package dfm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when a change during play breaks the rules
+var (
+	// ErrNoFatePoints is returned when spending a fate point without any left
+	ErrNoFatePoints = errors.New("no fate points left")
+	// ErrStressFull is returned when marking stress on a track with every box checked
+	ErrStressFull = errors.New("stress track is full")
+	// ErrStressEmpty is returned when clearing stress on a track with no boxes checked
+	ErrStressEmpty = errors.New("stress track is empty")
+	// ErrNoStressTrack is returned for a stress track the character does not have
+	ErrNoStressTrack = errors.New("character has no such stress track")
+	// ErrNoFreeConsequence is returned when every consequence of a level is already taken
+	ErrNoFreeConsequence = errors.New("no free consequence of that level")
+	// ErrNoActiveConsequence is returned when recovering from a consequence level that is not taken
+	ErrNoActiveConsequence = errors.New("no active consequence of that level")
+)
+
+// StressTrack identifies one of the stress tracks of a character
+type StressTrack string
+
+const (
+	// StressPhysical is the physical stress track of every character
+	StressPhysical StressTrack = "physical"
+	// StressMental is the mental stress track of every character
+	StressMental StressTrack = "mental"
+	// StressHunger is the hunger stress track of vampires
+	StressHunger StressTrack = "hunger"
+)
+
+// SpendFatePoint uses one of the character's fate points.
+func (c *Character) SpendFatePoint() error {
+	if c.FatePoint <= 0 {
+		return ErrNoFatePoints
+	}
+	c.FatePoint--
+	return nil
+}
+
+// GainFatePoint gives the character a fate point, e.g. for a compel.
+func (c *Character) GainFatePoint() {
+	c.FatePoint++
+}
+
+// stress returns pointers to the current value and the limit of a stress track
+func (c *Character) stress(track StressTrack) (*int, *int, error) {
+	switch track {
+	case StressPhysical:
+		return &c.PhysicalStressCurrent, &c.PhysicalStressLimit, nil
+	case StressMental:
+		return &c.MentalStressCurrent, &c.MentalStressLimit, nil
+	case StressHunger:
+		if c.Spirit == string(SpiritVampire) {
+			return &c.HungerStressCurrent, &c.HungerStressLimit, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s", ErrNoStressTrack, track)
+}
+
+// MarkStress checks a box on a stress track. The limit of the track cannot be exceeded.
+func (c *Character) MarkStress(track StressTrack) error {
+	current, limit, err := c.stress(track)
+	if err != nil {
+		return err
+	}
+	if *current >= *limit {
+		return fmt.Errorf("%w: %s %d/%d", ErrStressFull, track, *current, *limit)
+	}
+	*current++
+	return nil
+}
+
+// ClearStress clears a box on a stress track.
+func (c *Character) ClearStress(track StressTrack) error {
+	current, _, err := c.stress(track)
+	if err != nil {
+		return err
+	}
+	if *current <= 0 {
+		return fmt.Errorf("%w: %s", ErrStressEmpty, track)
+	}
+	*current--
+	return nil
+}
+
+// CanTakeConsequence checks that the character has a free consequence of the given level.
+func (c Character) CanTakeConsequence(level int) error {
+	for _, consequence := range c.Consequences {
+		if consequence.Level == level && !consequence.IsActive {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrNoFreeConsequence, level)
+}
+
+// TakeConsequence fills the first free consequence of the given level with a title.
+// Consequences of other levels are never filled instead.
+func (c *Character) TakeConsequence(level int, title string) error {
+	if title == "" {
+		return errors.New("a consequence needs a title")
+	}
+	for i, consequence := range c.Consequences {
+		if consequence.Level == level && !consequence.IsActive {
+			c.Consequences[i].IsActive = true
+			c.Consequences[i].Title = title
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrNoFreeConsequence, level)
+}
+
+// RecoverConsequence clears the last active consequence of the given level.
+func (c *Character) RecoverConsequence(level int) error {
+	for i := len(c.Consequences) - 1; i >= 0; i-- {
+		if c.Consequences[i].Level == level && c.Consequences[i].IsActive {
+			c.Consequences[i].IsActive = false
+			c.Consequences[i].Title = ""
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrNoActiveConsequence, level)
+}
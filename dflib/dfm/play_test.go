@@ -0,0 +1,101 @@
+package dfm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFatePoints(t *testing.T) {
+	character := Character{FatePoint: 1}
+
+	if err := character.SpendFatePoint(); err != nil {
+		t.Fatalf("SpendFatePoint failed: %v", err)
+	}
+	if err := character.SpendFatePoint(); !errors.Is(err, ErrNoFatePoints) {
+		t.Errorf("Expected ErrNoFatePoints, got %v", err)
+	}
+	character.GainFatePoint()
+	character.GainFatePoint()
+	if character.FatePoint != 2 {
+		t.Errorf("Expected 2 fate points, got %d", character.FatePoint)
+	}
+}
+
+func TestStressTracks(t *testing.T) {
+	character := NewCharacter(SpiritHuman)
+	character.PhysicalStressLimit = 2
+
+	for range 2 {
+		if err := character.MarkStress(StressPhysical); err != nil {
+			t.Fatalf("MarkStress failed: %v", err)
+		}
+	}
+	if err := character.MarkStress(StressPhysical); !errors.Is(err, ErrStressFull) {
+		t.Errorf("Expected ErrStressFull, got %v", err)
+	}
+	if character.PhysicalStressCurrent != 2 {
+		t.Errorf("Expected physical stress 2, got %d", character.PhysicalStressCurrent)
+	}
+
+	if err := character.ClearStress(StressMental); !errors.Is(err, ErrStressEmpty) {
+		t.Errorf("Expected ErrStressEmpty, got %v", err)
+	}
+	if err := character.ClearStress(StressPhysical); err != nil || character.PhysicalStressCurrent != 1 {
+		t.Errorf("ClearStress failed: %v, stress %d", err, character.PhysicalStressCurrent)
+	}
+
+	// Only vampires have a hunger track
+	if err := character.MarkStress(StressHunger); !errors.Is(err, ErrNoStressTrack) {
+		t.Errorf("Expected ErrNoStressTrack for a human, got %v", err)
+	}
+	vampire := NewCharacter(SpiritVampire)
+	if err := vampire.MarkStress(StressHunger); err != nil || vampire.HungerStressCurrent != 1 {
+		t.Errorf("MarkStress hunger failed: %v, stress %d", err, vampire.HungerStressCurrent)
+	}
+}
+
+func TestConsequences(t *testing.T) {
+	character := NewCharacter(SpiritHuman)
+	// A second mild consequence slot
+	character.Consequences = append(character.Consequences, Consequence{Level: 2})
+
+	if err := character.TakeConsequence(4, "Broken Arm"); err != nil {
+		t.Fatalf("TakeConsequence failed: %v", err)
+	}
+	if err := character.CanTakeConsequence(4); !errors.Is(err, ErrNoFreeConsequence) {
+		t.Errorf("Expected ErrNoFreeConsequence from CanTakeConsequence, got %v", err)
+	}
+	// A full level is not filled into a slot of another level
+	if err := character.TakeConsequence(4, "Concussion"); !errors.Is(err, ErrNoFreeConsequence) {
+		t.Errorf("Expected ErrNoFreeConsequence, got %v", err)
+	}
+	if err := character.TakeConsequence(3, "Bruised"); !errors.Is(err, ErrNoFreeConsequence) {
+		t.Errorf("Expected ErrNoFreeConsequence for a level without slots, got %v", err)
+	}
+	if err := character.TakeConsequence(2, ""); err == nil {
+		t.Error("Expected an error for a consequence without title")
+	}
+
+	// Both mild slots can be taken
+	for _, title := range []string{"Bruised", "Winded"} {
+		if err := character.TakeConsequence(2, title); err != nil {
+			t.Fatalf("TakeConsequence failed: %v", err)
+		}
+	}
+	if err := character.Validate(); err != nil {
+		t.Errorf("Expected a valid character, got %v", err)
+	}
+
+	if err := character.RecoverConsequence(2); err != nil {
+		t.Fatalf("RecoverConsequence failed: %v", err)
+	}
+	if last := character.Consequences[3]; last.IsActive || last.Title != "" {
+		t.Errorf("Expected the last mild consequence to be recovered, got %+v", last)
+	}
+	if err := character.RecoverConsequence(6); !errors.Is(err, ErrNoActiveConsequence) {
+		t.Errorf("Expected ErrNoActiveConsequence, got %v", err)
+	}
+	if character.Consequences[0].Title != "Bruised" || character.Consequences[1].Title != "Broken Arm" {
+		t.Errorf("Unexpected consequences: %+v", character.Consequences)
+	}
+}
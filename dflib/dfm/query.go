@@ -1,8 +1,30 @@
 package dfm
 
-// CharacterQuery defines filters for listing characters.
-// All filters are combined with AND logic.
-// Empty string values mean "match all" for that field.
+// CharacterSort is the order of listed characters
+type CharacterSort string
+
+const (
+	// SortByName orders characters by name, ignoring case
+	SortByName CharacterSort = "name"
+	// SortByEmbraceYear orders characters by year of embrace, earliest first
+	SortByEmbraceYear CharacterSort = "embrace_year"
+	// SortByModified orders characters by the time of their last change, oldest first
+	SortByModified CharacterSort = "modified"
+)
+
+// QueryMatch is how the filters of a query are combined
+type QueryMatch string
+
+const (
+	// MatchAll requires every filter to match (AND)
+	MatchAll QueryMatch = "all"
+	// MatchAny requires at least one filter to match (OR)
+	MatchAny QueryMatch = "any"
+)
+
+// CharacterQuery defines filters, sort order and pagination for listing characters.
+// Filters are combined with AND logic, or OR logic with MatchAny.
+// Empty values mean "match all" for that field. See Apply.
 type CharacterQuery struct {
 	// Spirit filters by character type: "vampire", "ghoul", "human", or empty for all
 	Spirit string
@@ -10,4 +32,63 @@ type CharacterQuery struct {
 	Player string
 	// Group filters by character group: "pc", "npc", or empty for all
 	Group string
+	// Tags filters by tags, ignoring case. Each tag is a filter of its own
+	Tags []string
+	// Collectives filters by collectives, ignoring case. Each collective is a filter of its own
+	Collectives []string
+	// Aspect filters by text in the title or description of an aspect, ignoring case
+	Aspect string
+	// Name filters by text in the name or an alias, ignoring case, or empty for all
+	Name string
+	// Fuzzy matches Name fuzzily: its letters in order, not necessarily next to each other
+	Fuzzy bool
+	// Match combines the filters: MatchAll (default) or MatchAny
+	Match QueryMatch
+	// Sort orders the characters: SortByName (default), SortByEmbraceYear or SortByModified.
+	// Characters in the same position are ordered by name and ID
+	Sort CharacterSort
+	// Descending reverses the sort order
+	Descending bool
+	// Offset skips characters from the start of the sorted result
+	Offset int
+	// Limit is the maximum number of characters returned, or 0 for all
+	Limit int
+}
+
+// ChronicleQuery defines filters for listing chronicles.
+// All filters are combined with AND logic.
+// Empty string values mean "match all" for that field.
+type ChronicleQuery struct {
+	// Gamemaster filters by gamemaster username, or empty for all
+	Gamemaster string
+	// Member filters by username taking part as gamemaster or player, or empty for all
+	Member string
+}
+
+// CampaignQuery defines filters for listing campaigns.
+// All filters are combined with AND logic.
+// Empty string values mean "match all" for that field.
+type CampaignQuery struct {
+	// ChronicleID filters by parent chronicle, or empty for all
+	ChronicleID string
+	// Gamemaster filters by gamemaster username, or empty for all
+	Gamemaster string
+	// Member filters by username taking part as gamemaster or player, or empty for all
+	Member string
+	// Character filters by member character ID, or empty for all
+	Character string
+}
+
+// SessionQuery defines filters for listing sessions.
+// All filters are combined with AND logic.
+// Empty string values mean "match all" for that field.
+type SessionQuery struct {
+	// CampaignID filters by parent campaign, or empty for all
+	CampaignID string
+	// Gamemaster filters by gamemaster username, or empty for all
+	Gamemaster string
+	// Member filters by username taking part as gamemaster or player, or empty for all
+	Member string
+	// Character filters by member character ID, or empty for all
+	Character string
 }
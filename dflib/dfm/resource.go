@@ -0,0 +1,13 @@
+package dfm
+
+import "time"
+
+// Resource is a downloadable file, such as a PDF, belonging to a chronicle or campaign.
+type Resource struct {
+	// Name is the file name of the resource
+	Name string `json:"name" yaml:"name"`
+	// Size is the size of the file in bytes
+	Size int64 `json:"size" yaml:"size"`
+	// ModifiedAt is when the file was last modified
+	ModifiedAt time.Time `json:"modifiedAt" yaml:"modifiedAt"`
+}
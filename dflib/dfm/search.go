@@ -0,0 +1,144 @@
+package dfm
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Matches reports whether a character matches the filters of the query.
+// A query without filters matches every character.
+func (q CharacterQuery) Matches(c Character) bool {
+	var results []bool
+	if q.Spirit != "" {
+		results = append(results, c.Spirit == q.Spirit)
+	}
+	if q.Player != "" {
+		results = append(results, c.Player == q.Player)
+	}
+	if q.Group != "" {
+		results = append(results, c.Group == q.Group)
+	}
+	for _, tag := range q.Tags {
+		results = append(results, containsFold(c.Tags, tag))
+	}
+	for _, collective := range q.Collectives {
+		results = append(results, containsFold(c.Collectives, collective))
+	}
+	if q.Aspect != "" {
+		results = append(results, slices.ContainsFunc(c.Aspects, func(a Aspect) bool {
+			return containsText(a.Title, q.Aspect) || containsText(a.Description, q.Aspect)
+		}))
+	}
+	if q.Name != "" {
+		results = append(results, q.matchesName(c))
+	}
+
+	if len(results) == 0 {
+		return true
+	}
+	if q.Match == MatchAny {
+		return slices.Contains(results, true)
+	}
+	return !slices.Contains(results, false)
+}
+
+// matchesName reports whether the name or an alias of a character matches the Name filter
+func (q CharacterQuery) matchesName(c Character) bool {
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if q.Fuzzy {
+			if _, ok := FuzzyMatch(q.Name, name); ok {
+				return true
+			}
+		} else if containsText(name, q.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the characters matching the query, sorted and paginated.
+// The given slice is not modified.
+func (q CharacterQuery) Apply(characters []Character) []Character {
+	matching := []Character{}
+	for _, character := range characters {
+		if q.Matches(character) {
+			matching = append(matching, character)
+		}
+	}
+	q.SortCharacters(matching)
+
+	start := min(max(q.Offset, 0), len(matching))
+	end := len(matching)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	return matching[start:end]
+}
+
+// SortCharacters sorts characters in place in the order of the query.
+// Characters in the same position are ordered by name and then ID, so the order is stable.
+func (q CharacterQuery) SortCharacters(characters []Character) {
+	slices.SortStableFunc(characters, func(a, b Character) int {
+		var order int
+		switch q.Sort {
+		case SortByEmbraceYear:
+			order = cmp.Compare(a.EmbraceYear, b.EmbraceYear)
+		case SortByModified:
+			order = a.Modified.Compare(b.Modified)
+		}
+		if order == 0 {
+			order = cmp.Or(
+				cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)),
+				cmp.Compare(a.ID, b.ID),
+			)
+		}
+		if q.Descending {
+			return -order
+		}
+		return order
+	})
+}
+
+// FuzzyMatch reports whether the letters of the pattern appear in the text in order,
+// ignoring case and spaces in the pattern. The score is higher for letters next to
+// each other and at the start of words, for ranking matches.
+func FuzzyMatch(pattern, text string) (score int, ok bool) {
+	patternRunes := []rune(strings.ToLower(strings.ReplaceAll(pattern, " ", "")))
+	if len(patternRunes) == 0 {
+		return 0, true
+	}
+
+	textRunes := []rune(strings.ToLower(text))
+	next, previous := 0, -2
+	for i, r := range textRunes {
+		if r != patternRunes[next] {
+			continue
+		}
+		score++
+		if i == previous+1 {
+			score += 2 // Consecutive letters
+		}
+		if i == 0 || !unicode.IsLetter(textRunes[i-1]) && !unicode.IsDigit(textRunes[i-1]) {
+			score += 3 // Start of a word
+		}
+		previous = i
+		if next++; next == len(patternRunes) {
+			return score, true
+		}
+	}
+	return 0, false
+}
+
+// containsFold reports whether the values contain the value, ignoring case
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+// containsText reports whether the text contains the substring, ignoring case
+func containsText(text, substring string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(substring))
+}
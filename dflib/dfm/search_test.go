@@ -0,0 +1,112 @@
+package dfm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		wantOK        bool
+	}{
+		{"", "Anything", true},
+		{"lcs", "Lucius Aurelius", true},
+		{"LA", "lucius aurelius", true},
+		{"lucius a", "Lucius Aurelius", true},
+		{"sul", "Lucius", false},
+		{"lucius x", "Lucius Aurelius", false},
+	}
+	for _, tt := range tests {
+		if _, ok := FuzzyMatch(tt.pattern, tt.text); ok != tt.wantOK {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.text, ok, tt.wantOK)
+		}
+	}
+
+	// Letters next to each other and at word starts score higher
+	consecutive, _ := FuzzyMatch("luc", "Lucius")
+	scattered, _ := FuzzyMatch("lcs", "Lucius")
+	if consecutive <= scattered {
+		t.Errorf("Consecutive score %d should be higher than scattered score %d", consecutive, scattered)
+	}
+	wordStart, _ := FuzzyMatch("ma", "Maria Alvarez")
+	inWord, _ := FuzzyMatch("ri", "Maria Alvarez")
+	if wordStart <= inWord {
+		t.Errorf("Word start score %d should be higher than in-word score %d", wordStart, inWord)
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	c := Character{
+		Name: "Maria Santos", Aliases: []string{"La Rosa"}, Spirit: "ghoul", Group: "pc",
+		Tags: []string{"Informant"}, Collectives: []string{"Church"},
+		Aspects: []Aspect{{Title: "Devoted Servant", Description: "Loyal to her domitor"}},
+	}
+
+	tests := []struct {
+		name  string
+		query CharacterQuery
+		want  bool
+	}{
+		{"No filters", CharacterQuery{}, true},
+		{"Tag ignoring case", CharacterQuery{Tags: []string{"informant"}}, true},
+		{"Missing tag", CharacterQuery{Tags: []string{"informant", "elder"}}, false},
+		{"Any tag", CharacterQuery{Tags: []string{"informant", "elder"}, Match: MatchAny}, true},
+		{"Collective", CharacterQuery{Collectives: []string{"church"}}, true},
+		{"Aspect description", CharacterQuery{Aspect: "DOMITOR"}, true},
+		{"Alias", CharacterQuery{Name: "rosa"}, true},
+		{"Fuzzy alias", CharacterQuery{Name: "lrs", Fuzzy: true}, true},
+		{"Substring is not fuzzy", CharacterQuery{Name: "lrs"}, false},
+		{"Wrong spirit", CharacterQuery{Spirit: "vampire", Name: "maria"}, false},
+		{"Any of spirit and name", CharacterQuery{Spirit: "vampire", Name: "maria", Match: MatchAny}, true},
+		{"None of spirit and name", CharacterQuery{Spirit: "vampire", Name: "john", Match: MatchAny}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(c); got != tt.want {
+				t.Errorf("Matches(%+v) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryApply(t *testing.T) {
+	now := time.Now()
+	characters := []Character{
+		{ID: "id-1", Name: "charlie", EmbraceYear: 1890, Modified: now},
+		{ID: "id-2", Name: "Alice", EmbraceYear: 1990, Modified: now.Add(-time.Hour)},
+		{ID: "id-3", Name: "Bob", EmbraceYear: 1890, Modified: now.Add(time.Hour)},
+		{ID: "id-0", Name: "Bob", EmbraceYear: 1890},
+	}
+
+	tests := []struct {
+		name  string
+		query CharacterQuery
+		want  string
+	}{
+		{"Name, then ID", CharacterQuery{}, "[id-2 id-0 id-3 id-1]"},
+		{"Descending", CharacterQuery{Descending: true}, "[id-1 id-3 id-0 id-2]"},
+		{"Embrace year", CharacterQuery{Sort: SortByEmbraceYear}, "[id-0 id-3 id-1 id-2]"},
+		{"Modified", CharacterQuery{Sort: SortByModified}, "[id-0 id-2 id-1 id-3]"},
+		{"Page", CharacterQuery{Offset: 1, Limit: 2}, "[id-0 id-3]"},
+		{"Negative offset", CharacterQuery{Offset: -1, Limit: 1}, "[id-2]"},
+		{"Filter and page", CharacterQuery{Name: "b", Offset: 1}, "[id-3]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, character := range tt.query.Apply(characters) {
+				ids = append(ids, character.ID)
+			}
+			if fmt.Sprint(ids) != tt.want {
+				t.Errorf("Apply(%+v) = %v, want %s", tt.query, ids, tt.want)
+			}
+		})
+	}
+
+	// The given slice is not reordered
+	if characters[0].ID != "id-1" {
+		t.Error("Apply reordered the given characters")
+	}
+}
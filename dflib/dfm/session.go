@@ -0,0 +1,33 @@
+package dfm
+
+import (
+	"slices"
+	"time"
+)
+
+// Session represents a Dark Fate gaming session set in a campaign.
+type Session struct {
+	// ID is the unique identifier (UUID v4)
+	ID string `json:"id" yaml:"id"`
+	// CampaignID is the ID of the campaign the session belongs to
+	CampaignID string `json:"campaignId" yaml:"campaignId"`
+	// Name is the display name of the session
+	Name string `json:"name" yaml:"name"`
+	// Gamemaster is the username of the gamemaster running the session
+	Gamemaster string `json:"gamemaster" yaml:"gamemaster"`
+	// Players is the list of usernames taking part in the session
+	Players []string `json:"players" yaml:"players"`
+	// Characters is the list of IDs of the characters present in the session
+	Characters []string `json:"characters" yaml:"characters"`
+	// PlayedAt is when the session was played
+	PlayedAt time.Time `json:"playedAt" yaml:"playedAt"`
+	// CreatedAt is when the session was created
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// UpdatedAt is when the session was last modified
+	UpdatedAt time.Time `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// IsMember reports whether the user is the gamemaster or one of the players of the session
+func (s Session) IsMember(username string) bool {
+	return username == s.Gamemaster || slices.Contains(s.Players, username)
+}
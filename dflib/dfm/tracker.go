@@ -0,0 +1,57 @@
+package dfm
+
+import "time"
+
+// TrackerEntryType identifies the kind of a Fate tracker log entry
+type TrackerEntryType string
+
+const (
+	// TrackerEntryRoll is a dice roll
+	TrackerEntryRoll TrackerEntryType = "roll"
+	// TrackerEntryTurn is a change of turn
+	TrackerEntryTurn TrackerEntryType = "turn"
+)
+
+// SessionTracker is the stored Fate tracker and dice roller log of a game session.
+type SessionTracker struct {
+	// SessionID identifies the game session the log belongs to
+	SessionID string `json:"sessionId" yaml:"sessionId"`
+	// Entries are the logged rolls and turn changes in chronological order
+	Entries []TrackerEntry `json:"entries" yaml:"entries"`
+}
+
+// TrackerEntry is a single event in a session's Fate tracker log.
+type TrackerEntry struct {
+	// Type is the kind of the entry: "roll" or "turn"
+	Type TrackerEntryType `json:"type" yaml:"type"`
+	// Time is when the entry was recorded
+	Time time.Time `json:"time" yaml:"time"`
+	// Username is the user who rolled or changed the turn
+	Username string `json:"username" yaml:"username"`
+	// Character is the name of the character acting, if any
+	Character string `json:"character,omitempty" yaml:"character,omitempty"`
+	// Dice are the rolled Fate dice faces (-1, 0 or 1), roll entries only
+	Dice []int `json:"dice,omitempty" yaml:"dice,omitempty"`
+	// Skill is the title of the skill used for the roll, roll entries only
+	Skill string `json:"skill,omitempty" yaml:"skill,omitempty"`
+	// SkillRating is the rating of the skill used for the roll, roll entries only
+	SkillRating int `json:"skillRating,omitempty" yaml:"skillRating,omitempty"`
+	// Modifier is any other bonus or penalty applied to the roll, roll entries only
+	Modifier int `json:"modifier,omitempty" yaml:"modifier,omitempty"`
+	// Invokes are the aspects invoked for the roll, roll entries only
+	Invokes []Invoke `json:"invokes,omitempty" yaml:"invokes,omitempty"`
+	// Total is the final result of the roll, roll entries only
+	Total int `json:"total,omitempty" yaml:"total,omitempty"`
+	// Turn is the name of the character or user whose turn begins, turn entries only
+	Turn string `json:"turn,omitempty" yaml:"turn,omitempty"`
+}
+
+// Invoke is an aspect invoked to improve a roll.
+type Invoke struct {
+	// Aspect is the title of the invoked aspect
+	Aspect string `json:"aspect" yaml:"aspect"`
+	// Bonus is the bonus gained from the invoke (usually +2)
+	Bonus int `json:"bonus" yaml:"bonus"`
+	// Free is true if the invoke did not cost a fate point
+	Free bool `json:"free" yaml:"free"`
+}
@@ -0,0 +1,38 @@
+package dfm
+
+// User is a registered user of the Dark Fate TUI.
+type User struct {
+	// Username is the SSH login username
+	Username string `json:"username" yaml:"username"`
+	// DisplayName is the name shown to other users
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	// Role is the user's role: "player", "gamemaster" or "admin"
+	Role Role `json:"role" yaml:"role"`
+	// PublicKeys is the list of the user's authorized SSH public keys in authorized_keys format
+	PublicKeys []string `json:"publicKeys" yaml:"publicKeys"`
+}
+
+// Name returns the display name of the user, or the username if no display name is set.
+func (u User) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
+// Role represents what a user is allowed to see and do
+type Role string
+
+const (
+	// RolePlayer is a user who plays their own characters
+	RolePlayer Role = "player"
+	// RoleGamemaster is a user who runs chronicles, campaigns and sessions
+	RoleGamemaster Role = "gamemaster"
+	// RoleAdmin is a user with access to everything
+	RoleAdmin Role = "admin"
+)
+
+// Valid reports whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	return r == RolePlayer || r == RoleGamemaster || r == RoleAdmin
+}
@@ -0,0 +1,140 @@
+package dfm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rating limits enforced when validating characters
+const (
+	// MaxSkillRating is the highest skill rating, Superb (+5) on the Fate ladder
+	MaxSkillRating = 5
+	// MaxDisciplineRating is the highest vampire discipline rating
+	MaxDisciplineRating = 5
+	// MaxBloodPotency is the highest potency of vampire's blood
+	MaxBloodPotency = 10
+	// MaxStressLimit is the highest number of slots in a stress track
+	MaxStressLimit = 6
+)
+
+// validNamePattern matches the names that can be stored in file names: letters, digits and spaces
+var validNamePattern = regexp.MustCompile(`^[a-zA-Z0-9 ]+$`)
+
+// ValidName reports whether a name of a character, chronicle, campaign or session can be
+// stored: it may contain only letters, digits and spaces.
+func ValidName(name string) bool {
+	return validNamePattern.MatchString(name)
+}
+
+// FieldError describes why the value of a character field is invalid.
+type FieldError struct {
+	// Field is the JSON name of the field, with an index for list items (e.g. "skills[3]")
+	Field string
+	// Message explains what is wrong with the value
+	Message string
+}
+
+// Error returns the field name and the message
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists the invalid fields of a character.
+type ValidationError struct {
+	// Fields are the invalid fields in the order they were checked
+	Fields []FieldError
+}
+
+// Error returns all field errors in one line
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return "invalid character: " + strings.Join(messages, "; ")
+}
+
+// Validate checks the character against the Dark Fate character rules.
+// It returns nil for a valid character or a *ValidationError listing every invalid field.
+func (c Character) Validate() error {
+	var errs []FieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Names are optional, as in the character storage
+	if c.Name != "" && !ValidName(c.Name) {
+		add("name", "may contain only letters, digits and spaces")
+	}
+	for i, alias := range c.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			add(fmt.Sprintf("aliases[%d]", i), "must not be empty")
+		}
+	}
+	for i, tag := range c.Tags {
+		if strings.TrimSpace(tag) == "" {
+			add(fmt.Sprintf("tags[%d]", i), "must not be empty")
+		}
+	}
+
+	if c.Refresh < 0 {
+		add("refresh", "must not be negative")
+	}
+	if c.FatePoint < 0 {
+		add("fatePoint", "must not be negative")
+	}
+
+	for i, skill := range c.Skills {
+		if skill.Rating < 0 || skill.Rating > MaxSkillRating {
+			add(fmt.Sprintf("skills[%d]", i), "rating must be between 0 and %d", MaxSkillRating)
+		}
+	}
+	for i, stunt := range c.Stunts {
+		if strings.TrimSpace(stunt.Title) == "" && strings.TrimSpace(stunt.Description) != "" {
+			add(fmt.Sprintf("stunts[%d]", i), "a described stunt needs a title")
+		}
+	}
+
+	if c.Spirit == string(SpiritVampire) {
+		if c.BloodPotency < 0 || c.BloodPotency > MaxBloodPotency {
+			add("bloodPotency", "must be between 0 and %d", MaxBloodPotency)
+		}
+		for i, discipline := range c.Disciplines {
+			if discipline.Rating < 0 || discipline.Rating > MaxDisciplineRating {
+				add(fmt.Sprintf("disciplines[%d]", i), "rating must be between 0 and %d", MaxDisciplineRating)
+			}
+		}
+	} else if len(c.Disciplines) > 0 {
+		add("disciplines", "only vampires have disciplines")
+	}
+
+	for i, consequence := range c.Consequences {
+		field := fmt.Sprintf("consequences[%d]", i)
+		if consequence.Level != 2 && consequence.Level != 4 && consequence.Level != 6 {
+			add(field, "level must be 2, 4 or 6")
+		}
+		if consequence.IsActive && strings.TrimSpace(consequence.Title) == "" {
+			add(field, "an active consequence needs a title")
+		}
+	}
+
+	validateStress := func(track string, current, limit int) {
+		if limit < 0 || limit > MaxStressLimit {
+			add(track+"StressLimit", "must be between 0 and %d", MaxStressLimit)
+		}
+		if current < 0 || current > limit {
+			add(track+"StressCurrent", "must be between 0 and the limit %d", limit)
+		}
+	}
+	validateStress("physical", c.PhysicalStressCurrent, c.PhysicalStressLimit)
+	validateStress("mental", c.MentalStressCurrent, c.MentalStressLimit)
+	if c.Spirit == string(SpiritVampire) {
+		validateStress("hunger", c.HungerStressCurrent, c.HungerStressLimit)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
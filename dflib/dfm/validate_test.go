@@ -0,0 +1,113 @@
+package dfm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// validVampire returns a vampire character that passes validation
+func validVampire() Character {
+	return Character{
+		ID:           "550e8400-e29b-41d4-a716-446655440000",
+		Spirit:       string(SpiritVampire),
+		Group:        string(PC),
+		Name:         "Test Vampire",
+		Aliases:      []string{"The Dark One"},
+		Refresh:      3,
+		FatePoint:    2,
+		BloodPotency: 1,
+		Skills: []Skill{
+			{Title: "academics", Group: "mental", Rating: 2},
+		},
+		Disciplines: []Discipline{
+			{Title: "auspex", Rating: 3},
+		},
+		Consequences: []Consequence{
+			{Level: 2},
+			{Level: 4, IsActive: true, Title: "Broken Arm"},
+			{Level: 6},
+		},
+		PhysicalStressLimit:   3,
+		PhysicalStressCurrent: 1,
+		MentalStressLimit:     3,
+		HungerStressLimit:     3,
+		HungerStressCurrent:   3,
+	}
+}
+
+func TestValidateValidCharacter(t *testing.T) {
+	if err := validVampire().Validate(); err != nil {
+		t.Errorf("Expected valid character, got %v", err)
+	}
+}
+
+func TestValidateInvalidFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Character)
+		field  string
+	}{
+		{"Invalid name", func(c *Character) { c.Name = "Bad/Name" }, "name"},
+		{"Empty alias", func(c *Character) { c.Aliases = []string{"ok", ""} }, "aliases[1]"},
+		{"Negative fate points", func(c *Character) { c.FatePoint = -1 }, "fatePoint"},
+		{"Skill rating too high", func(c *Character) { c.Skills[0].Rating = MaxSkillRating + 1 }, "skills[0]"},
+		{"Negative skill rating", func(c *Character) { c.Skills[0].Rating = -1 }, "skills[0]"},
+		{"Untitled stunt with description", func(c *Character) { c.Stunts = []Stunt{{Description: "Does things"}} }, "stunts[0]"},
+		{"Discipline rating too high", func(c *Character) { c.Disciplines[0].Rating = MaxDisciplineRating + 1 }, "disciplines[0]"},
+		{"Disciplines on a human", func(c *Character) { c.Spirit = string(SpiritHuman) }, "disciplines"},
+		{"Blood potency too high", func(c *Character) { c.BloodPotency = MaxBloodPotency + 1 }, "bloodPotency"},
+		{"Invalid consequence level", func(c *Character) { c.Consequences[0].Level = 3 }, "consequences[0]"},
+		{"Active consequence without title", func(c *Character) { c.Consequences[2].IsActive = true }, "consequences[2]"},
+		{"Stress above limit", func(c *Character) { c.PhysicalStressCurrent = 4 }, "physicalStressCurrent"},
+		{"Negative stress", func(c *Character) { c.MentalStressCurrent = -1 }, "mentalStressCurrent"},
+		{"Stress limit too high", func(c *Character) { c.MentalStressLimit = MaxStressLimit + 1 }, "mentalStressLimit"},
+		{"Hunger above limit", func(c *Character) { c.HungerStressCurrent = 4 }, "hungerStressCurrent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			character := validVampire()
+			tt.modify(&character)
+
+			err := character.Validate()
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *ValidationError, got %v", err)
+			}
+			if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != tt.field {
+				t.Errorf("Expected one error for %s, got %v", tt.field, validationErr.Fields)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("Expected error message to name %s, got %q", tt.field, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateIgnoresHungerOfNonVampires(t *testing.T) {
+	character := validVampire()
+	character.Spirit = string(SpiritGhoul)
+	character.Disciplines = nil
+	character.HungerStressCurrent = 10
+
+	if err := character.Validate(); err != nil {
+		t.Errorf("Expected hunger to be ignored for ghouls, got %v", err)
+	}
+}
+
+func TestCloneSharesNoSlices(t *testing.T) {
+	original := validVampire()
+	clone := original.Clone()
+
+	clone.Aliases[0] = "Changed"
+	clone.Skills[0].Rating = 5
+	clone.Consequences[1].Title = "Changed"
+
+	if original.Aliases[0] != "The Dark One" || original.Skills[0].Rating != 2 || original.Consequences[1].Title != "Broken Arm" {
+		t.Errorf("Modifying the clone changed the original: %+v", original)
+	}
+	if clone.Tags != nil {
+		t.Errorf("Expected nil slices to stay nil, got %v", clone.Tags)
+	}
+}
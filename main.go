@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"io/fs"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -16,26 +19,62 @@ import (
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
+	"github.com/charmbracelet/wish/scp"
 	"github.com/hkionline/dftui/services"
 	"github.com/hkionline/dftui/ui"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 var (
-	port    = flag.String("port", "2222", "Port to listen on")
-	hostKey = flag.String("host-key", "", "Path to host key (default: ~/.dftui/id_rsa)")
+	configPath = flag.String("config", services.DefaultConfigFile, "Path to YAML or JSON config file")
+	port       = flag.String("port", "", "Port to listen on, overrides listen in the config (default: 2222)")
+	host       = flag.String("host", "", "Host name users connect to, shown in download commands (default: localhost)")
+	hostKey    = flag.String("host-key", "", "Path to host key (default: ~/.dftui/id_rsa)")
 )
 
+// authMessageShown is the connection context key set when a rejected user has been told why
+type authMessageShownKey struct{}
+
+var authMessageShown = authMessageShownKey{}
+
 func main() {
 	flag.Parse()
 
+	config, err := loadConfig()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	_, listenPort, err := net.SplitHostPort(config.Listen)
+	if err != nil {
+		log.Fatal("Invalid listen address:", err)
+	}
+
+	// Log to a file instead of standard error if configured
+	if config.Logging.File != "" {
+		logFile, err := os.OpenFile(config.Logging.File, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			log.Fatal("Failed to open log file:", err)
+		}
+		defer logFile.Close()
+		log.SetOutput(logFile)
+	}
+
 	// Initialize backend service using dfdb
-	backend, err := services.NewDFDBBackend()
+	backend, err := services.NewDFDBBackend(config)
 	if err != nil {
 		log.Fatal("Failed to initialize backend:", err)
 	}
 
+	// Without registered users nobody can log in
+	if users, err := backend.GetUsers(); err == nil && len(users) == 0 {
+		log.Printf("Warning: no users registered in %s, all connections will be rejected", config.Users)
+	}
+
+	// Session hub relays session-mode dice rolls between connected users
+	hub := services.NewSessionHub()
+
 	// Determine host key path
-	keyPath := *hostKey
+	keyPath := config.HostKey
 	if keyPath == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -50,29 +89,69 @@ func main() {
 		log.Fatal("Failed to create host key directory:", err)
 	}
 
+	// Middleware runs from last to first
+	middleware := []wish.Middleware{
+		// Bubble Tea middleware - creates TUI for each session
+		bubbletea.Middleware(func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+			// Username is trusted after public-key authentication against the user registry
+			username := s.User()
+
+			// Connect the user to the session hub and to character changes,
+			// and disconnect when the SSH session ends
+			client := hub.NewClient(username)
+			changes, unsubscribe := backend.SubscribeCharacterChanges()
+			go func() {
+				<-s.Context().Done()
+				client.Close()
+				unsubscribe()
+			}()
+
+			// Create new model for this user session
+			m := ui.NewModel(username, backend, client, changes, net.JoinHostPort(config.Host, listenPort))
+
+			// Return model with alt screen buffer (clears screen on start/exit)
+			return m, []tea.ProgramOption{
+				tea.WithAltScreen(),
+				tea.WithMouseCellMotion(),
+			}
+		}),
+		// SCP middleware - read-only resource downloads, handled before the TUI
+		scp.Middleware(services.NewResourceHandler(backend), nil),
+	}
+	if config.Logging.Connections {
+		// Logging middleware logs each connection to the server log
+		middleware = append(middleware, logging.MiddlewareWithLogger(log.Default()))
+	}
+
 	// Create SSH server with Wish
 	// The bubbletea middleware creates a new Bubble Tea program for each SSH session
 	s, err := wish.NewServer(
-		wish.WithAddress(":"+*port),
+		wish.WithAddress(config.Listen),
 		wish.WithHostKeyPath(keyPath),
-		wish.WithMiddleware(
-			// Bubble Tea middleware - creates TUI for each session
-			bubbletea.Middleware(func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
-				// Extract username from SSH session (task 2.2)
-				username := s.User()
-
-				// Create new model for this user session
-				m := ui.NewModel(username, backend)
-
-				// Return model with alt screen buffer (clears screen on start/exit)
-				return m, []tea.ProgramOption{
-					tea.WithAltScreen(),
-					tea.WithMouseCellMotion(),
-				}
-			}),
-			// Logging middleware for debugging
-			logging.Middleware(),
-		),
+		// Only public keys registered in the user registry are accepted
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			if _, err := backend.AuthenticatePublicKey(ctx.User(), key); err != nil {
+				log.Printf("Rejected public key %s for %s: %v", gossh.FingerprintSHA256(key), ctx.User(), err)
+				return false
+			}
+			return true
+		}),
+		// Keyboard-interactive auth never succeeds; it is used to tell rejected users why
+		wish.WithKeyboardInteractiveAuth(func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+			// Clients retry keyboard-interactive auth, show the message only once per connection
+			if ctx.Value(authMessageShown) != nil {
+				return false
+			}
+			ctx.SetValue(authMessageShown, true)
+
+			_, err := backend.GetUser(ctx.User())
+			if err == nil {
+				err = services.ErrUnknownKey
+			}
+			challenger("", services.AuthErrorMessage(ctx.User(), err), nil, nil)
+			return false
+		}),
+		wish.WithMiddleware(middleware...),
 	)
 	if err != nil {
 		log.Fatal("Failed to create server:", err)
@@ -86,7 +165,7 @@ func main() {
 	go func() {
 		addr := lipgloss.NewStyle().Bold(true).Render(s.Addr)
 		log.Printf("Starting SSH server on %s", addr)
-		log.Printf("Connect with: ssh localhost -p %s", *port)
+		log.Printf("Connect with: ssh %s -p %s", config.Host, listenPort)
 		if err := s.ListenAndServe(); err != nil {
 			log.Fatal("Server error:", err)
 		}
@@ -107,3 +186,37 @@ func main() {
 
 	log.Println("Server stopped")
 }
+
+// loadConfig loads the config file and applies the flags given on the command line.
+// Without a -config flag, a missing default config file leaves the default configuration.
+func loadConfig() (services.Config, error) {
+	config, err := services.LoadConfig(*configPath)
+	if errors.Is(err, fs.ErrNotExist) && !isFlagSet("config") {
+		config, err = services.DefaultConfig(), nil
+	}
+	if err != nil {
+		return services.Config{}, err
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			listenHost, _, _ := net.SplitHostPort(config.Listen)
+			config.Listen = net.JoinHostPort(listenHost, *port)
+		case "host":
+			config.Host = *host
+		case "host-key":
+			config.HostKey = *hostKey
+		}
+	})
+	return config, nil
+}
+
+// isFlagSet reports whether a flag was given on the command line
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// ErrPermissionDenied is returned when a user changes data they are not allowed to change
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Access decides what a user is allowed to see and change. It is built from the user
+// and the campaigns, so the rules can be tested without storage or SSH:
+//
+//   - Admins see everything.
+//   - Gamemasters see all characters of the campaigns they run.
+//   - Players see their own characters, the PCs of the campaigns they play in
+//     and the NPCs the gamemaster has revealed in those campaigns.
+//   - Chronicles and campaigns are visible to their gamemaster and players.
+//   - Characters can be edited by admins, their player and the gamemasters
+//     of the campaigns they are in.
+type Access struct {
+	user     dfm.User
+	gmOf     map[string]bool // IDs of characters in campaigns the user runs
+	playerOf map[string]bool // IDs of characters in campaigns the user plays in
+	revealed map[string]bool // IDs of NPCs revealed in campaigns the user plays in
+}
+
+// NewAccess creates the access rules of a user taking part in the given campaigns.
+// Campaigns the user is not a member of are ignored.
+func NewAccess(user dfm.User, campaigns []dfm.Campaign) Access {
+	access := Access{
+		user:     user,
+		gmOf:     make(map[string]bool),
+		playerOf: make(map[string]bool),
+		revealed: make(map[string]bool),
+	}
+
+	for _, campaign := range campaigns {
+		switch {
+		case campaign.Gamemaster == user.Username:
+			for _, id := range campaign.Characters {
+				access.gmOf[id] = true
+			}
+		case slices.Contains(campaign.Players, user.Username):
+			for _, id := range campaign.Characters {
+				access.playerOf[id] = true
+			}
+			for _, id := range campaign.Revealed {
+				access.revealed[id] = true
+			}
+		}
+	}
+	return access
+}
+
+// IsAdmin reports whether the user can see everything.
+func (a Access) IsAdmin() bool {
+	return a.user.Role == dfm.RoleAdmin
+}
+
+// CanSeeCharacter reports whether the user may see a character.
+func (a Access) CanSeeCharacter(character dfm.Character) bool {
+	switch {
+	case a.IsAdmin():
+		return true
+	case character.Player == a.user.Username:
+		return true
+	case a.gmOf[character.ID]:
+		return true
+	case character.Group == string(dfm.PC):
+		return a.playerOf[character.ID]
+	default:
+		return a.revealed[character.ID]
+	}
+}
+
+// CanEditCharacter reports whether the user may change a character.
+func (a Access) CanEditCharacter(character dfm.Character) bool {
+	return a.IsAdmin() || character.Player == a.user.Username || a.gmOf[character.ID]
+}
+
+// CanSeeChronicle reports whether the user may see a chronicle and its resources.
+func (a Access) CanSeeChronicle(chronicle dfm.Chronicle) bool {
+	return a.IsAdmin() || chronicle.IsMember(a.user.Username)
+}
+
+// CanSeeCampaign reports whether the user may see a campaign, its sessions and its resources.
+func (a Access) CanSeeCampaign(campaign dfm.Campaign) bool {
+	return a.IsAdmin() || campaign.IsMember(a.user.Username)
+}
+
+// FilterCharacters returns the characters the user may see, PCs before NPCs.
+// The order within PCs and NPCs is kept.
+func (a Access) FilterCharacters(characters []dfm.Character) []dfm.Character {
+	visible := make([]dfm.Character, 0, len(characters))
+	for _, character := range characters {
+		if a.CanSeeCharacter(character) {
+			visible = append(visible, character)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].Group == string(dfm.PC) && visible[j].Group != string(dfm.PC)
+	})
+	return visible
+}
+
+// userAccess loads the access rules of a user.
+// Users missing from the registry get the access of a player.
+func (b *DFDBBackend) userAccess(username string) (Access, error) {
+	user, err := b.GetUser(username)
+	if errors.Is(err, ErrUnknownUser) {
+		user, err = dfm.User{Username: username, Role: dfm.RolePlayer}, nil
+	}
+	if err != nil {
+		return Access{}, err
+	}
+
+	campaigns, err := b.campaigns.ListCampaigns(dfm.CampaignQuery{Member: username})
+	if err != nil {
+		return Access{}, fmt.Errorf("failed to load campaigns: %w", err)
+	}
+	return NewAccess(user, campaigns), nil
+}
+
+// userChronicle loads a chronicle, returning ErrPermissionDenied if the user may not see it.
+func (b *DFDBBackend) userChronicle(username, chronicleID string) (dfm.Chronicle, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return dfm.Chronicle{}, err
+	}
+	chronicle, err := b.campaigns.ReadChronicle(chronicleID)
+	if err != nil {
+		return dfm.Chronicle{}, fmt.Errorf("failed to load chronicle: %w", err)
+	}
+	if !access.CanSeeChronicle(chronicle) {
+		return dfm.Chronicle{}, fmt.Errorf("%w: %s may not see chronicle %s", ErrPermissionDenied, username, chronicleID)
+	}
+	return chronicle, nil
+}
+
+// userCampaign loads a campaign, returning ErrPermissionDenied if the user may not see it.
+func (b *DFDBBackend) userCampaign(username, campaignID string) (dfm.Campaign, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return dfm.Campaign{}, err
+	}
+	campaign, err := b.campaigns.ReadCampaign(campaignID)
+	if err != nil {
+		return dfm.Campaign{}, fmt.Errorf("failed to load campaign: %w", err)
+	}
+	if !access.CanSeeCampaign(campaign) {
+		return dfm.Campaign{}, fmt.Errorf("%w: %s may not see campaign %s", ErrPermissionDenied, username, campaignID)
+	}
+	return campaign, nil
+}
+
+// userSession loads a stored session, returning ErrPermissionDenied if the user
+// may not see its campaign. Sessions without a campaign are open to admins and
+// the gamemaster and players of the session.
+func (b *DFDBBackend) userSession(username, sessionID string) (dfm.Session, error) {
+	session, err := b.campaigns.ReadSession(sessionID)
+	if err != nil {
+		return dfm.Session{}, fmt.Errorf("failed to load session: %w", err)
+	}
+	if session.CampaignID == "" {
+		access, err := b.userAccess(username)
+		if err != nil {
+			return dfm.Session{}, err
+		}
+		if !access.IsAdmin() && !session.IsMember(username) {
+			return dfm.Session{}, fmt.Errorf("%w: %s may not see session %s", ErrPermissionDenied, username, sessionID)
+		}
+		return session, nil
+	}
+	if _, err := b.userCampaign(username, session.CampaignID); err != nil {
+		return dfm.Session{}, err
+	}
+	return session, nil
+}
+
+// CanJoinSession checks that the user may join a game session and use its Fate tracker log.
+// Sessions stored in a campaign are open to the members of the campaign, others get
+// an error matching ErrPermissionDenied. Ad hoc sessions are open to everyone.
+func (b *DFDBBackend) CanJoinSession(username, sessionID string) error {
+	_, err := b.userSession(username, sessionID)
+	if errors.Is(err, dfdb.ErrSessionNotFound) {
+		return nil
+	}
+	return err
+}
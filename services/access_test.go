@@ -0,0 +1,181 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestAccessCanSeeCharacter(t *testing.T) {
+	campaigns := []dfm.Campaign{
+		{
+			ID:         "winter-court",
+			Gamemaster: "gm",
+			Players:    []string{"alice", "bob"},
+			Characters: []string{"alice-pc", "bob-pc", "secret-npc", "revealed-npc"},
+			Revealed:   []string{"revealed-npc"},
+		},
+		{
+			ID:         "other-table",
+			Gamemaster: "othergm",
+			Players:    []string{"carol"},
+			Characters: []string{"carol-pc", "other-npc"},
+			Revealed:   []string{"other-npc"},
+		},
+	}
+
+	characters := map[string]dfm.Character{
+		"alice-pc":     {ID: "alice-pc", Player: "alice", Group: string(dfm.PC)},
+		"bob-pc":       {ID: "bob-pc", Player: "bob", Group: string(dfm.PC)},
+		"carol-pc":     {ID: "carol-pc", Player: "carol", Group: string(dfm.PC)},
+		"secret-npc":   {ID: "secret-npc", Player: "gm", Group: string(dfm.NPC)},
+		"revealed-npc": {ID: "revealed-npc", Player: "gm", Group: string(dfm.NPC)},
+		"other-npc":    {ID: "other-npc", Player: "othergm", Group: string(dfm.NPC)},
+		"alice-spare":  {ID: "alice-spare", Player: "alice", Group: string(dfm.PC)},
+		"loose-npc":    {ID: "loose-npc", Player: "gm", Group: string(dfm.NPC)},
+	}
+
+	tests := []struct {
+		name    string
+		user    dfm.User
+		visible []string
+	}{
+		{
+			name:    "Player sees own and campaign-mates' PCs and revealed NPCs",
+			user:    dfm.User{Username: "alice", Role: dfm.RolePlayer},
+			visible: []string{"alice-pc", "bob-pc", "revealed-npc", "alice-spare"},
+		},
+		{
+			name:    "Player in another campaign",
+			user:    dfm.User{Username: "carol", Role: dfm.RolePlayer},
+			visible: []string{"carol-pc", "other-npc"},
+		},
+		{
+			name:    "Gamemaster sees all characters in their campaigns and their own",
+			user:    dfm.User{Username: "gm", Role: dfm.RoleGamemaster},
+			visible: []string{"alice-pc", "bob-pc", "secret-npc", "revealed-npc", "loose-npc"},
+		},
+		{
+			name:    "Gamemaster role alone grants nothing in other campaigns",
+			user:    dfm.User{Username: "newgm", Role: dfm.RoleGamemaster},
+			visible: nil,
+		},
+		{
+			name:    "Admin sees everything",
+			user:    dfm.User{Username: "root", Role: dfm.RoleAdmin},
+			visible: []string{"alice-pc", "bob-pc", "carol-pc", "secret-npc", "revealed-npc", "other-npc", "alice-spare", "loose-npc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access := NewAccess(tt.user, campaigns)
+
+			want := make(map[string]bool)
+			for _, id := range tt.visible {
+				want[id] = true
+			}
+			for id, character := range characters {
+				if got := access.CanSeeCharacter(character); got != want[id] {
+					t.Errorf("CanSeeCharacter(%s) = %v, want %v", id, got, want[id])
+				}
+			}
+		})
+	}
+}
+
+func TestAccessCanSeeChroniclesAndCampaigns(t *testing.T) {
+	chronicle := dfm.Chronicle{ID: "helsinki", Gamemaster: "gm", Players: []string{"alice"}}
+	campaign := dfm.Campaign{ID: "winter-court", Gamemaster: "gm", Players: []string{"bob"}}
+
+	tests := []struct {
+		user         dfm.User
+		seeChronicle bool
+		seeCampaign  bool
+	}{
+		{dfm.User{Username: "gm", Role: dfm.RoleGamemaster}, true, true},
+		{dfm.User{Username: "alice", Role: dfm.RolePlayer}, true, false},
+		{dfm.User{Username: "bob", Role: dfm.RolePlayer}, false, true},
+		{dfm.User{Username: "mallory", Role: dfm.RolePlayer}, false, false},
+		{dfm.User{Username: "root", Role: dfm.RoleAdmin}, true, true},
+	}
+
+	for _, tt := range tests {
+		access := NewAccess(tt.user, []dfm.Campaign{campaign})
+		if got := access.CanSeeChronicle(chronicle); got != tt.seeChronicle {
+			t.Errorf("%s: CanSeeChronicle = %v, want %v", tt.user.Username, got, tt.seeChronicle)
+		}
+		if got := access.CanSeeCampaign(campaign); got != tt.seeCampaign {
+			t.Errorf("%s: CanSeeCampaign = %v, want %v", tt.user.Username, got, tt.seeCampaign)
+		}
+	}
+}
+
+func TestAccessFilterCharactersOrdersPCsFirst(t *testing.T) {
+	access := NewAccess(dfm.User{Username: "root", Role: dfm.RoleAdmin}, nil)
+	characters := []dfm.Character{
+		{ID: "npc-1", Group: string(dfm.NPC)},
+		{ID: "pc-1", Group: string(dfm.PC)},
+		{ID: "npc-2", Group: string(dfm.NPC)},
+		{ID: "pc-2", Group: string(dfm.PC)},
+	}
+
+	filtered := access.FilterCharacters(characters)
+	var ids []string
+	for _, character := range filtered {
+		ids = append(ids, character.ID)
+	}
+	want := []string{"pc-1", "pc-2", "npc-1", "npc-2"}
+	for i := range want {
+		if i >= len(ids) || ids[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, ids)
+		}
+	}
+}
+
+func TestGetUserCharactersAppliesAccess(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	characters := []dfm.Character{
+		{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Alice PC", Group: string(dfm.PC), Spirit: string(dfm.SpiritHuman), Player: "alice", Category: "character"},
+		{ID: "550e8400-e29b-41d4-a716-446655440001", Name: "Secret NPC", Group: string(dfm.NPC), Spirit: string(dfm.SpiritVampire), Player: "gm", Category: "character"},
+	}
+	// Characters created directly are recorded in the history in the background,
+	// wait for that before the directory is removed
+	t.Cleanup(backend.history.Close)
+	for _, character := range characters {
+		if err := backend.provider.Create(character); err != nil {
+			t.Fatalf("Failed to create character: %v", err)
+		}
+	}
+	if err := backend.campaigns.CreateCampaign(dfm.Campaign{
+		ID:         "550e8400-e29b-41d4-a716-446655440200",
+		Name:       "Winter Court",
+		Gamemaster: "gm",
+		Players:    []string{"alice"},
+		Characters: []string{characters[0].ID, characters[1].ID},
+	}); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
+	// The NPC is not revealed, so alice only sees her PC
+	results, err := backend.GetUserCharacters("alice")
+	if err != nil {
+		t.Fatalf("GetUserCharacters failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "Alice PC" {
+		t.Errorf("Unexpected characters for alice: %+v", results)
+	}
+
+	// The gamemaster sees both
+	results, err = backend.GetUserCharacters("gm")
+	if err != nil {
+		t.Fatalf("GetUserCharacters failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Name != "Alice PC" {
+		t.Errorf("Unexpected characters for gm: %+v", results)
+	}
+}
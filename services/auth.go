@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// ErrUnknownUser is returned when authenticating a user who is not in the user registry
+var ErrUnknownUser = errors.New("user is not registered")
+
+// ErrUnknownKey is returned when a public key is not registered for the user
+var ErrUnknownKey = errors.New("public key is not registered for the user")
+
+// GetUser returns a registered user by username
+func (b *DFDBBackend) GetUser(username string) (dfm.User, error) {
+	user, err := b.users.ReadUser(username)
+	if errors.Is(err, dfdb.ErrUserNotFound) {
+		return dfm.User{}, fmt.Errorf("%w: %s", ErrUnknownUser, username)
+	}
+	if err != nil {
+		return dfm.User{}, fmt.Errorf("failed to load user: %w", err)
+	}
+	return user, nil
+}
+
+// GetUsers returns all registered users sorted by username
+func (b *DFDBBackend) GetUsers() ([]dfm.User, error) {
+	users, err := b.users.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	return users, nil
+}
+
+// AuthenticatePublicKey checks that a public key is registered for the user
+// and returns the user. Registered keys that cannot be parsed are skipped
+// with a warning.
+func (b *DFDBBackend) AuthenticatePublicKey(username string, key ssh.PublicKey) (dfm.User, error) {
+	user, err := b.GetUser(username)
+	if err != nil {
+		return dfm.User{}, err
+	}
+
+	for _, authorized := range user.PublicKeys {
+		registered, _, _, _, err := gossh.ParseAuthorizedKey([]byte(authorized))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: invalid public key for user %s: %v\n", username, err)
+			continue
+		}
+		if ssh.KeysEqual(registered, key) {
+			return user, nil
+		}
+	}
+	return dfm.User{}, fmt.Errorf("%w: %s", ErrUnknownKey, username)
+}
+
+// AuthErrorMessage returns the message shown to a user whose connection is rejected
+func AuthErrorMessage(username string, err error) string {
+	switch {
+	case errors.Is(err, ErrUnknownUser):
+		return fmt.Sprintf("Access denied: user %q is not registered.\nAsk an admin to add you to the user registry (db/users.json).\n", username)
+	case errors.Is(err, ErrUnknownKey):
+		return fmt.Sprintf("Access denied: none of your public keys is registered for user %q.\nAsk an admin to add your public key to the user registry (db/users.json).\n", username)
+	default:
+		return fmt.Sprintf("Access denied for user %q: %v\n", username, err)
+	}
+}
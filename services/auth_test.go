@@ -0,0 +1,100 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newTestPublicKey generates an ed25519 public key
+func newTestPublicKey(t *testing.T) gossh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("Failed to create public key: %v", err)
+	}
+	return key
+}
+
+func TestAuthenticatePublicKey(t *testing.T) {
+	dir := t.TempDir()
+	aliceKey := newTestPublicKey(t)
+	aliceOtherKey := newTestPublicKey(t)
+	strangerKey := newTestPublicKey(t)
+
+	users := []dfm.User{
+		{
+			Username:    "alice",
+			DisplayName: "Alice",
+			Role:        dfm.RolePlayer,
+			PublicKeys: []string{
+				"not a key",
+				strings.TrimSpace(string(gossh.MarshalAuthorizedKey(aliceKey))) + " alice@laptop",
+				string(gossh.MarshalAuthorizedKey(aliceOtherKey)),
+			},
+		},
+		{Username: "gm", Role: dfm.RoleGamemaster},
+	}
+	data, _ := json.Marshal(users)
+	if err := os.WriteFile(filepath.Join(dir, dfdb.UsersFile), data, 0644); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	backend, err := NewDFDBBackendForTest(dir)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		key      gossh.PublicKey
+		wantErr  error
+	}{
+		{"Registered key with comment", "alice", aliceKey, nil},
+		{"Second registered key", "alice", aliceOtherKey, nil},
+		{"Unregistered key", "alice", strangerKey, ErrUnknownKey},
+		{"Another user's key", "gm", aliceKey, ErrUnknownKey},
+		{"Unknown user", "mallory", aliceKey, ErrUnknownUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := backend.AuthenticatePublicKey(tt.username, tt.key)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AuthenticatePublicKey failed: %v", err)
+			}
+			if user.Username != tt.username || user.Role != dfm.RolePlayer {
+				t.Errorf("Unexpected user: %+v", user)
+			}
+		})
+	}
+}
+
+func TestAuthErrorMessage(t *testing.T) {
+	if msg := AuthErrorMessage("mallory", ErrUnknownUser); !strings.Contains(msg, `user "mallory" is not registered`) {
+		t.Errorf("Unexpected message for unknown user: %q", msg)
+	}
+	if msg := AuthErrorMessage("alice", ErrUnknownKey); !strings.Contains(msg, "public key") || !strings.Contains(msg, `"alice"`) {
+		t.Errorf("Unexpected message for unknown key: %q", msg)
+	}
+}
@@ -1,8 +1,13 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
 
+	"github.com/charmbracelet/ssh"
 	"github.com/hkionline/dftui/dflib/dfdb"
 	"github.com/hkionline/dftui/dflib/dfm"
 )
@@ -11,54 +16,422 @@ import (
 type Backend interface {
 	// GetUserCharacters returns the characters visible to a user
 	GetUserCharacters(username string) ([]dfm.Character, error)
+	// CreateCharacter validates and stores a new character of the user
+	CreateCharacter(username string, character dfm.Character) error
+	// UpdateCharacter validates and stores changes to a character the user may edit,
+	// returning the stored character. The changes must be based on the stored revision.
+	UpdateCharacter(username string, character dfm.Character) (dfm.Character, error)
+	// GetCharacterHistory returns the history of a character the user may see, oldest entry first
+	GetCharacterHistory(username, characterID string) ([]dfm.CharacterHistoryEntry, error)
+	// GetDeletedCharacters returns the deletions of characters the user may restore
+	GetDeletedCharacters(username string) ([]dfm.CharacterHistoryEntry, error)
+	// RestoreCharacter restores the version of a character in a history entry, also for
+	// a deleted character, returning the restored character
+	RestoreCharacter(username, characterID string, sequence int) (dfm.Character, error)
+	// SubscribeCharacterChanges returns a channel of character changes made by anyone
+	// and a function that ends the subscription
+	SubscribeCharacterChanges() (<-chan dfdb.ChangeEvent, func())
+	// CanJoinSession returns nil if the user may join a game session, or an error
+	// matching ErrPermissionDenied if the session belongs to a campaign the user is not in
+	CanJoinSession(username, sessionID string) error
+	// AppendSessionLog records entries in the Fate tracker log of a game session the user may join
+	AppendSessionLog(username, sessionID string, entries ...dfm.TrackerEntry) error
+	// GetSessionLog returns the stored Fate tracker log of a game session the user may join
+	GetSessionLog(username, sessionID string) (dfm.SessionTracker, error)
+	// GetUserChronicles returns the chronicles a user takes part in
+	GetUserChronicles(username string) ([]dfm.Chronicle, error)
+	// GetChronicleReadme returns the Markdown README of a chronicle the user takes part in
+	GetChronicleReadme(username, chronicleID string) (string, error)
+	// GetChronicleResources returns the downloadable resource files of a chronicle the user takes part in
+	GetChronicleResources(username, chronicleID string) ([]dfm.Resource, error)
+	// GetUserCampaigns returns the campaigns a user takes part in
+	GetUserCampaigns(username string) ([]dfm.Campaign, error)
+	// GetCampaign returns a campaign the user takes part in by ID
+	GetCampaign(username, campaignID string) (dfm.Campaign, error)
+	// GetCampaignReadme returns the Markdown README of a campaign the user takes part in
+	GetCampaignReadme(username, campaignID string) (string, error)
+	// GetCampaignResources returns the downloadable resource files of a campaign the user takes part in
+	GetCampaignResources(username, campaignID string) ([]dfm.Resource, error)
+	// GetCampaignSessions returns the sessions of a campaign the user takes part in, in play order
+	GetCampaignSessions(username, campaignID string) ([]dfm.Session, error)
+	// GetSession returns a session of a campaign the user takes part in by ID
+	GetSession(username, sessionID string) (dfm.Session, error)
+	// GetSessionNotes returns the Markdown notes of a session of a campaign the user takes part in
+	GetSessionNotes(username, sessionID string) (string, error)
+	// OpenUserResource opens a resource file by its download path if the user may see it
+	OpenUserResource(username, resourcePath string) (fs.File, error)
+	// GetUser returns a registered user by username
+	GetUser(username string) (dfm.User, error)
+	// GetUsers returns all registered users
+	GetUsers() ([]dfm.User, error)
+	// AuthenticatePublicKey returns the user if the public key is registered for them
+	AuthenticatePublicKey(username string, key ssh.PublicKey) (dfm.User, error)
 }
 
 // DFDBBackend implements the Backend interface using dfdb filesystem provider
 type DFDBBackend struct {
-	provider dfdb.Provider
+	provider  dfdb.Provider
+	history   *dfdb.HistoryRecorder
+	trackers  dfdb.TrackerProvider
+	campaigns dfdb.CampaignProvider
+	users     dfdb.UserProvider
 }
 
-// NewDFDBBackend creates a new backend service using dfdb
-func NewDFDBBackend() (*DFDBBackend, error) {
-	// Initialize dfdb with the db directory
-	provider, err := dfdb.NewFsProvider("db/characters")
+// NewDFDBBackend creates a new backend service using dfdb with the configured
+// character provider, database directory and user registry
+func NewDFDBBackend(config Config) (*DFDBBackend, error) {
+	provider, err := dfdb.NewProvider(config.Characters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize dfdb provider: %w", err)
 	}
+	// Pick up character files added or edited outside the application
+	if watcher, ok := provider.(dfdb.Watcher); ok {
+		if err := watcher.Watch(); err != nil {
+			return nil, fmt.Errorf("failed to watch character files: %w", err)
+		}
+	}
 
-	return &DFDBBackend{provider: provider}, nil
+	// Every change to a character is recorded in its history
+	history, err := dfdb.NewFsHistoryProvider(filepath.Join(config.Database, dfdb.HistoryDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dfdb history provider: %w", err)
+	}
+
+	// Chronicles, campaigns and sessions are stored in their own db subdirectories
+	campaigns, err := dfdb.NewFsCampaignProvider(config.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dfdb campaign provider: %w", err)
+	}
+
+	// Session Fate tracker logs are stored next to the session data
+	trackers, err := dfdb.NewFsTrackerProvider(filepath.Join(config.Database, dfdb.SessionsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dfdb tracker provider: %w", err)
+	}
+
+	// Registered users and their public keys
+	users, err := dfdb.NewFsUserProvider(config.Users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize dfdb user provider: %w", err)
+	}
+
+	return &DFDBBackend{
+		provider:  provider,
+		history:   dfdb.NewHistoryRecorder(provider, history),
+		trackers:  trackers,
+		campaigns: campaigns,
+		users:     users,
+	}, nil
 }
 
-// GetUserCharacters loads character data from db/characters directory using dfdb
-// Returns PCs for the specified username and all NPCs
+// GetUserCharacters loads the characters the user may see from the dfdb character provider.
+// See Access for the visibility rules. PCs are returned before NPCs.
 func (b *DFDBBackend) GetUserCharacters(username string) ([]dfm.Character, error) {
-	// Create query to get PCs for this user
-	pcQuery := dfm.CharacterQuery{
-		Player: username,
-		Group:  string(dfm.PC),
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get user's PCs
-	pcs, err := b.provider.List(pcQuery)
+	characters, err := b.provider.List(dfm.CharacterQuery{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to load PC characters: %w", err)
+		return nil, fmt.Errorf("failed to load characters: %w", err)
 	}
 
-	// Create query for all NPCs (no player filter)
-	npcQuery := dfm.CharacterQuery{
-		Group: string(dfm.NPC),
+	return access.FilterCharacters(characters), nil
+}
+
+// CreateCharacter drops blank list entries, validates the character and stores it with dfdb, recording it in its history.
+// Users create characters for themselves; only admins may create characters for others.
+func (b *DFDBBackend) CreateCharacter(username string, character dfm.Character) error {
+	character.DropBlankEntries()
+	if err := character.Validate(); err != nil {
+		return err
 	}
 
-	// Get all NPCs
-	npcs, err := b.provider.List(npcQuery)
+	access, err := b.userAccess(username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load NPC characters: %w", err)
+		return err
+	}
+	if character.Player != username && !access.IsAdmin() {
+		return fmt.Errorf("%w: %s may not create characters for %s", ErrPermissionDenied, username, character.Player)
 	}
 
-	// Combine results - PCs first, then NPCs
-	characters := make([]dfm.Character, 0, len(pcs)+len(npcs))
-	characters = append(characters, pcs...)
-	characters = append(characters, npcs...)
+	if err := b.history.Create(username, character); err != nil {
+		return fmt.Errorf("failed to create character: %w", err)
+	}
+	return nil
+}
+
+// UpdateCharacter drops blank list entries, validates the character and stores it with dfdb, recording the changes in its history.
+// The permission check uses the stored character, so a user cannot take over
+// a character by changing its player. The revision of the character must be the
+// stored one, otherwise an error matching dfdb.ErrStaleRevision is returned.
+func (b *DFDBBackend) UpdateCharacter(username string, character dfm.Character) (dfm.Character, error) {
+	character.DropBlankEntries()
+	if err := character.Validate(); err != nil {
+		return character, err
+	}
+
+	access, err := b.userAccess(username)
+	if err != nil {
+		return character, err
+	}
+
+	stored, err := b.provider.Read(character.ID)
+	if err != nil {
+		return character, fmt.Errorf("failed to load character: %w", err)
+	}
+	if !access.CanEditCharacter(stored) {
+		return character, fmt.Errorf("%w: %s may not edit %s", ErrPermissionDenied, username, stored.Name)
+	}
+
+	if err := b.history.Update(username, character, character.Revision); err != nil {
+		return character, fmt.Errorf("failed to save character: %w", err)
+	}
+	// The provider stores the character at the next revision
+	character.Revision++
+	return character, nil
+}
 
-	return characters, nil
+// GetCharacterHistory returns the recorded changes of a character. The user must be
+// allowed to see the character, or the deleted character if it no longer exists.
+func (b *DFDBBackend) GetCharacterHistory(username, characterID string) ([]dfm.CharacterHistoryEntry, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := b.history.History(characterID)
+	if errors.Is(err, dfdb.ErrHistoryNotFound) {
+		return []dfm.CharacterHistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load character history: %w", err)
+	}
+
+	character, err := b.latestCharacter(characterID, entries)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanSeeCharacter(character) {
+		return nil, fmt.Errorf("%w: %s may not see %s", ErrPermissionDenied, username, character.Name)
+	}
+	return entries, nil
+}
+
+// GetDeletedCharacters returns the history entries of deleted characters that the user
+// could edit, and so may restore. The most recently deleted characters are returned first.
+func (b *DFDBBackend) GetDeletedCharacters(username string) ([]dfm.CharacterHistoryEntry, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := b.history.Deleted()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deleted characters: %w", err)
+	}
+
+	result := []dfm.CharacterHistoryEntry{}
+	for _, entry := range deleted {
+		if access.CanEditCharacter(entry.Character) {
+			result = append(result, entry)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b dfm.CharacterHistoryEntry) int {
+		return b.Time.Compare(a.Time)
+	})
+	return result, nil
+}
+
+// RestoreCharacter restores the version of a character stored in the history entry with
+// the given sequence. The user must be allowed to edit both the character as it is now,
+// or as it was deleted, and the restored version.
+func (b *DFDBBackend) RestoreCharacter(username, characterID string, sequence int) (dfm.Character, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return dfm.Character{}, err
+	}
+
+	entries, err := b.history.History(characterID)
+	if err != nil {
+		return dfm.Character{}, fmt.Errorf("failed to load character history: %w", err)
+	}
+	if sequence < 1 || sequence > len(entries) {
+		return dfm.Character{}, fmt.Errorf("%w: %s #%d", dfdb.ErrHistoryEntryNotFound, characterID, sequence)
+	}
+
+	character, err := b.latestCharacter(characterID, entries)
+	if err != nil {
+		return dfm.Character{}, err
+	}
+	for _, c := range []dfm.Character{character, entries[sequence-1].Character} {
+		if !access.CanEditCharacter(c) {
+			return dfm.Character{}, fmt.Errorf("%w: %s may not restore %s", ErrPermissionDenied, username, c.Name)
+		}
+	}
+
+	restored, err := b.history.Restore(username, characterID, sequence)
+	if err != nil {
+		return dfm.Character{}, fmt.Errorf("failed to restore character: %w", err)
+	}
+	return restored, nil
+}
+
+// latestCharacter returns the stored character, or its last recorded version if it has been deleted
+func (b *DFDBBackend) latestCharacter(characterID string, entries []dfm.CharacterHistoryEntry) (dfm.Character, error) {
+	character, err := b.provider.Read(characterID)
+	if errors.Is(err, dfdb.ErrCharacterNotFound) && len(entries) > 0 {
+		return entries[len(entries)-1].Character, nil
+	}
+	if err != nil {
+		return dfm.Character{}, fmt.Errorf("failed to load character: %w", err)
+	}
+	return character, nil
+}
+
+// SubscribeCharacterChanges subscribes to the characters created, updated and deleted
+// through the dfdb provider or in the characters directory. Events are not filtered by
+// user; subscribers reload what they may see with GetUserCharacters.
+func (b *DFDBBackend) SubscribeCharacterChanges() (<-chan dfdb.ChangeEvent, func()) {
+	return b.provider.Subscribe()
+}
+
+// AppendSessionLog records entries in a game session's Fate tracker log.
+// The log of a stored session can only be written by the members of its campaign.
+func (b *DFDBBackend) AppendSessionLog(username, sessionID string, entries ...dfm.TrackerEntry) error {
+	if err := b.CanJoinSession(username, sessionID); err != nil {
+		return err
+	}
+	if err := b.trackers.Append(sessionID, entries...); err != nil {
+		return fmt.Errorf("failed to append session log: %w", err)
+	}
+	return nil
+}
+
+// GetSessionLog returns the stored Fate tracker log of a game session.
+// The log of a stored session can only be read by the members of its campaign.
+func (b *DFDBBackend) GetSessionLog(username, sessionID string) (dfm.SessionTracker, error) {
+	if err := b.CanJoinSession(username, sessionID); err != nil {
+		return dfm.SessionTracker{}, err
+	}
+	tracker, err := b.trackers.Read(sessionID)
+	if err != nil {
+		return dfm.SessionTracker{}, fmt.Errorf("failed to load session log: %w", err)
+	}
+	return tracker, nil
+}
+
+// GetUserChronicles returns the chronicles where the user is the gamemaster or a player
+func (b *DFDBBackend) GetUserChronicles(username string) ([]dfm.Chronicle, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
+	}
+
+	chronicles, err := b.campaigns.ListChronicles(dfm.ChronicleQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chronicles: %w", err)
+	}
+	return slices.DeleteFunc(chronicles, func(chronicle dfm.Chronicle) bool {
+		return !access.CanSeeChronicle(chronicle)
+	}), nil
+}
+
+// GetChronicleReadme returns the Markdown README of a chronicle the user takes part in
+func (b *DFDBBackend) GetChronicleReadme(username, chronicleID string) (string, error) {
+	if _, err := b.userChronicle(username, chronicleID); err != nil {
+		return "", err
+	}
+	readme, err := b.campaigns.ReadChronicleReadme(chronicleID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load chronicle README: %w", err)
+	}
+	return readme, nil
+}
+
+// GetChronicleResources returns the downloadable resource files of a chronicle the user takes part in
+func (b *DFDBBackend) GetChronicleResources(username, chronicleID string) ([]dfm.Resource, error) {
+	if _, err := b.userChronicle(username, chronicleID); err != nil {
+		return nil, err
+	}
+	resources, err := b.campaigns.ListChronicleResources(chronicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chronicle resources: %w", err)
+	}
+	return resources, nil
+}
+
+// GetUserCampaigns returns the campaigns where the user is the gamemaster or a player
+func (b *DFDBBackend) GetUserCampaigns(username string) ([]dfm.Campaign, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns, err := b.campaigns.ListCampaigns(dfm.CampaignQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaigns: %w", err)
+	}
+	return slices.DeleteFunc(campaigns, func(campaign dfm.Campaign) bool {
+		return !access.CanSeeCampaign(campaign)
+	}), nil
+}
+
+// GetCampaign returns a campaign the user takes part in by ID
+func (b *DFDBBackend) GetCampaign(username, campaignID string) (dfm.Campaign, error) {
+	return b.userCampaign(username, campaignID)
+}
+
+// GetCampaignReadme returns the Markdown README of a campaign the user takes part in
+func (b *DFDBBackend) GetCampaignReadme(username, campaignID string) (string, error) {
+	if _, err := b.userCampaign(username, campaignID); err != nil {
+		return "", err
+	}
+	readme, err := b.campaigns.ReadCampaignReadme(campaignID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load campaign README: %w", err)
+	}
+	return readme, nil
+}
+
+// GetCampaignResources returns the downloadable resource files of a campaign the user takes part in
+func (b *DFDBBackend) GetCampaignResources(username, campaignID string) ([]dfm.Resource, error) {
+	if _, err := b.userCampaign(username, campaignID); err != nil {
+		return nil, err
+	}
+	resources, err := b.campaigns.ListCampaignResources(campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign resources: %w", err)
+	}
+	return resources, nil
+}
+
+// GetCampaignSessions returns the sessions of a campaign the user takes part in, in play order
+func (b *DFDBBackend) GetCampaignSessions(username, campaignID string) ([]dfm.Session, error) {
+	if _, err := b.userCampaign(username, campaignID); err != nil {
+		return nil, err
+	}
+	sessions, err := b.campaigns.ListSessions(dfm.SessionQuery{CampaignID: campaignID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetSession returns a session of a campaign the user takes part in by ID
+func (b *DFDBBackend) GetSession(username, sessionID string) (dfm.Session, error) {
+	return b.userSession(username, sessionID)
+}
+
+// GetSessionNotes returns the Markdown notes of a session of a campaign the user takes part in
+func (b *DFDBBackend) GetSessionNotes(username, sessionID string) (string, error) {
+	if _, err := b.userSession(username, sessionID); err != nil {
+		return "", err
+	}
+	notes, err := b.campaigns.ReadSessionNotes(sessionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load session notes: %w", err)
+	}
+	return notes, nil
 }
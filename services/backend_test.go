@@ -2,6 +2,7 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,15 +13,7 @@ import (
 
 // TestGetUserCharacters tests the GetUserCharacters function with dfdb
 func TestGetUserCharacters(t *testing.T) {
-	testDir := "test_characters"
-	defer os.RemoveAll(testDir)
-
-	// Create test directory
-	if err := os.MkdirAll(testDir, 0755); err != nil {
-		t.Fatalf("Failed to create test directory: %v", err)
-	}
-
-	// Create test character files
+	// Test characters
 	testChars := []dfm.Character{
 		{
 			ID:       "550e8400-e29b-41d4-a716-446655440000",
@@ -48,24 +41,24 @@ func TestGetUserCharacters(t *testing.T) {
 		},
 	}
 
-	// Write test character files
-	for _, char := range testChars {
-		filename := filepath.Join(testDir, char.Name+"_"+char.ID+".json")
-		data, err := json.MarshalIndent(char, "", "  ")
-		if err != nil {
-			t.Fatalf("Failed to marshal character: %v", err)
-		}
-		if err := os.WriteFile(filename, data, 0644); err != nil {
-			t.Fatalf("Failed to write character file: %v", err)
-		}
-	}
-
-	// Create backend with test directory
-	backend, err := NewDFDBBackendForTest(testDir)
+	// Seed the characters in memory
+	backend, err := newTestBackend(t.TempDir(), dfdb.NewMemoryProvider(testChars))
 	if err != nil {
 		t.Fatalf("Failed to create backend: %v", err)
 	}
 
+	// The NPC is visible to testuser because the gamemaster has revealed it in their campaign
+	if err := backend.campaigns.CreateCampaign(dfm.Campaign{
+		ID:         "550e8400-e29b-41d4-a716-446655440200",
+		Name:       "Test Campaign",
+		Gamemaster: "gm",
+		Players:    []string{"testuser"},
+		Characters: []string{testChars[0].ID, testChars[1].ID, testChars[2].ID},
+		Revealed:   []string{testChars[2].ID},
+	}); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
 	// Test getting characters for testuser
 	results, err := backend.GetUserCharacters("testuser")
 	if err != nil {
@@ -95,14 +88,7 @@ func TestGetUserCharacters(t *testing.T) {
 
 // TestGetUserCharactersEmpty tests behavior with no characters
 func TestGetUserCharactersEmpty(t *testing.T) {
-	testDir := "test_empty"
-	defer os.RemoveAll(testDir)
-
-	if err := os.MkdirAll(testDir, 0755); err != nil {
-		t.Fatalf("Failed to create test directory: %v", err)
-	}
-
-	backend, err := NewDFDBBackendForTest(testDir)
+	backend, err := newTestBackend(t.TempDir(), dfdb.NewMemoryProvider(nil))
 	if err != nil {
 		t.Fatalf("Failed to create backend: %v", err)
 	}
@@ -122,11 +108,559 @@ func TestGetUserCharactersEmpty(t *testing.T) {
 	}
 }
 
+// TestSessionLog tests storing and loading a session's Fate tracker log
+func TestSessionLog(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	entry := dfm.TrackerEntry{
+		Type:     dfm.TrackerEntryRoll,
+		Username: "testuser",
+		Dice:     []int{1, 1, 0, -1},
+		Total:    1,
+	}
+	if err := backend.AppendSessionLog("testuser", "session-1", entry); err != nil {
+		t.Fatalf("AppendSessionLog failed: %v", err)
+	}
+
+	tracker, err := backend.GetSessionLog("testuser", "session-1")
+	if err != nil {
+		t.Fatalf("GetSessionLog failed: %v", err)
+	}
+	if len(tracker.Entries) != 1 || tracker.Entries[0].Username != "testuser" {
+		t.Errorf("Unexpected session log entries: %+v", tracker.Entries)
+	}
+
+	if _, err := backend.GetSessionLog("testuser", "missing"); !errors.Is(err, dfdb.ErrTrackerNotFound) {
+		t.Errorf("Expected ErrTrackerNotFound, got %v", err)
+	}
+
+	// The log of a stored session is open only to the members of its campaign
+	campaign := dfm.Campaign{ID: "550e8400-e29b-41d4-a716-446655440200", Name: "Winter Court", Gamemaster: "gm", Players: []string{"testuser"}}
+	session := dfm.Session{ID: "550e8400-e29b-41d4-a716-446655440300", CampaignID: campaign.ID, Name: "Session 1"}
+	if err := backend.campaigns.CreateCampaign(campaign); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	if err := backend.campaigns.CreateSession(session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	for _, username := range []string{"testuser", "gm"} {
+		if err := backend.AppendSessionLog(username, session.ID, entry); err != nil {
+			t.Errorf("AppendSessionLog failed for %s: %v", username, err)
+		}
+	}
+	if err := backend.AppendSessionLog("otheruser", session.ID, entry); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied when appending, got %v", err)
+	}
+	if _, err := backend.GetSessionLog("otheruser", session.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied when reading, got %v", err)
+	}
+	if tracker, _ := backend.GetSessionLog("gm", session.ID); len(tracker.Entries) != 2 {
+		t.Errorf("Expected 2 entries from the members, got %d", len(tracker.Entries))
+	}
+}
+
+// TestSessionWithoutCampaign tests that a stored session without a campaign is open to
+// admins and the members of the session
+func TestSessionWithoutCampaign(t *testing.T) {
+	dir := t.TempDir()
+	data, _ := json.Marshal([]dfm.User{{Username: "root", Role: dfm.RoleAdmin}})
+	if err := os.WriteFile(filepath.Join(dir, dfdb.UsersFile), data, 0644); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	backend, err := NewDFDBBackendForTest(dir)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	session := dfm.Session{ID: "550e8400-e29b-41d4-a716-446655440302", Name: "One Shot", Gamemaster: "gm", Players: []string{"alice"}}
+	if err := backend.campaigns.CreateSession(session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	entry := dfm.TrackerEntry{Type: dfm.TrackerEntryRoll, Dice: []int{1, 0, 0, -1}}
+	for _, username := range []string{"root", "gm", "alice"} {
+		if err := backend.CanJoinSession(username, session.ID); err != nil {
+			t.Errorf("CanJoinSession failed for %s: %v", username, err)
+		}
+		if _, err := backend.GetSession(username, session.ID); err != nil {
+			t.Errorf("GetSession failed for %s: %v", username, err)
+		}
+		entry.Username = username
+		if err := backend.AppendSessionLog(username, session.ID, entry); err != nil {
+			t.Errorf("AppendSessionLog failed for %s: %v", username, err)
+		}
+	}
+
+	if err := backend.CanJoinSession("otheruser", session.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied when joining, got %v", err)
+	}
+	if _, err := backend.GetSessionLog("otheruser", session.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied when reading, got %v", err)
+	}
+	if tracker, _ := backend.GetSessionLog("root", session.ID); len(tracker.Entries) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(tracker.Entries))
+	}
+}
+
+// TestCanJoinSession tests that stored sessions are open only to the members of their campaign
+func TestCanJoinSession(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	campaign := dfm.Campaign{ID: "550e8400-e29b-41d4-a716-446655440201", Name: "Winter Court", Gamemaster: "gm", Players: []string{"player"}}
+	session := dfm.Session{ID: "550e8400-e29b-41d4-a716-446655440301", CampaignID: campaign.ID, Name: "Session 1"}
+	if err := backend.campaigns.CreateCampaign(campaign); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+	if err := backend.campaigns.CreateSession(session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	tests := []struct {
+		username, sessionID string
+		wantErr             bool
+	}{
+		{"gm", session.ID, false},
+		{"player", session.ID, false},
+		{"otheruser", session.ID, true},
+		{"otheruser", "ad-hoc", false},
+	}
+	for _, tt := range tests {
+		err := backend.CanJoinSession(tt.username, tt.sessionID)
+		if tt.wantErr && !errors.Is(err, ErrPermissionDenied) {
+			t.Errorf("CanJoinSession(%s, %s) = %v, want ErrPermissionDenied", tt.username, tt.sessionID, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("CanJoinSession(%s, %s) = %v, want nil", tt.username, tt.sessionID, err)
+		}
+	}
+}
+
+// TestGetUserChronicles tests listing chronicles and reading their READMEs
+func TestGetUserChronicles(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewDFDBBackendForTest(dir)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	chronicles := []dfm.Chronicle{
+		{ID: "550e8400-e29b-41d4-a716-446655440100", Name: "Helsinki by Night", Gamemaster: "gm", Players: []string{"testuser"}},
+		{ID: "550e8400-e29b-41d4-a716-446655440101", Name: "Secret Chronicle", Gamemaster: "gm"},
+	}
+	for _, chronicle := range chronicles {
+		if err := backend.campaigns.CreateChronicle(chronicle); err != nil {
+			t.Fatalf("Failed to create chronicle: %v", err)
+		}
+	}
+
+	results, err := backend.GetUserChronicles("testuser")
+	if err != nil {
+		t.Fatalf("GetUserChronicles failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "Helsinki by Night" {
+		t.Errorf("Unexpected chronicles for testuser: %+v", results)
+	}
+
+	readmeDir := filepath.Join(dir, dfdb.ChroniclesDir, chronicles[0].ID)
+	if err := os.MkdirAll(readmeDir, 0755); err != nil {
+		t.Fatalf("Failed to create README directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(readmeDir, dfdb.ReadmeFile), []byte("# Helsinki"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+
+	readme, err := backend.GetChronicleReadme("testuser", chronicles[0].ID)
+	if err != nil {
+		t.Fatalf("GetChronicleReadme failed: %v", err)
+	}
+	if readme != "# Helsinki" {
+		t.Errorf("README mismatch: got %q", readme)
+	}
+	if _, err := backend.GetChronicleReadme("otheruser", chronicles[0].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a non-member, got %v", err)
+	}
+	if _, err := backend.GetChronicleResources("testuser", chronicles[1].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a secret chronicle, got %v", err)
+	}
+}
+
+// TestGetUserCampaigns tests listing a user's campaigns and their sessions
+func TestGetUserCampaigns(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	campaigns := []dfm.Campaign{
+		{ID: "550e8400-e29b-41d4-a716-446655440200", Name: "Winter Court", Gamemaster: "gm", Players: []string{"testuser"}},
+		{ID: "550e8400-e29b-41d4-a716-446655440201", Name: "Other Table", Gamemaster: "gm", Players: []string{"otheruser"}},
+	}
+	for _, campaign := range campaigns {
+		if err := backend.campaigns.CreateCampaign(campaign); err != nil {
+			t.Fatalf("Failed to create campaign: %v", err)
+		}
+	}
+	session := dfm.Session{ID: "550e8400-e29b-41d4-a716-446655440300", CampaignID: campaigns[0].ID, Name: "Session 1"}
+	if err := backend.campaigns.CreateSession(session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	results, err := backend.GetUserCampaigns("testuser")
+	if err != nil {
+		t.Fatalf("GetUserCampaigns failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "Winter Court" {
+		t.Errorf("Unexpected campaigns for testuser: %+v", results)
+	}
+
+	sessions, err := backend.GetCampaignSessions("testuser", campaigns[0].ID)
+	if err != nil {
+		t.Fatalf("GetCampaignSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != session.ID {
+		t.Errorf("Unexpected sessions: %+v", sessions)
+	}
+
+	if _, err := backend.GetSessionNotes("testuser", session.ID); !errors.Is(err, dfdb.ErrDocumentNotFound) {
+		t.Errorf("Expected ErrDocumentNotFound, got %v", err)
+	}
+
+	got, err := backend.GetSession("testuser", session.ID)
+	if err != nil || got.CampaignID != campaigns[0].ID {
+		t.Errorf("GetSession returned %+v, %v", got, err)
+	}
+	campaign, err := backend.GetCampaign("testuser", got.CampaignID)
+	if err != nil || campaign.Name != "Winter Court" {
+		t.Errorf("GetCampaign returned %+v, %v", campaign, err)
+	}
+	if _, err := backend.GetSession("testuser", "550e8400-e29b-41d4-a716-446655440399"); !errors.Is(err, dfdb.ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	// Users outside the campaign see none of it
+	if _, err := backend.GetCampaign("otheruser", campaigns[0].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetCampaign, got %v", err)
+	}
+	if _, err := backend.GetCampaignReadme("otheruser", campaigns[0].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetCampaignReadme, got %v", err)
+	}
+	if _, err := backend.GetCampaignResources("otheruser", campaigns[0].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetCampaignResources, got %v", err)
+	}
+	if _, err := backend.GetCampaignSessions("otheruser", campaigns[0].ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetCampaignSessions, got %v", err)
+	}
+	if _, err := backend.GetSession("otheruser", session.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetSession, got %v", err)
+	}
+	if _, err := backend.GetSessionNotes("otheruser", session.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for GetSessionNotes, got %v", err)
+	}
+}
+
+func TestCreateCharacter(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	character := dfm.NewCharacter(dfm.SpiritVampire)
+	character.Name = "Alice PC"
+	character.Player = "alice"
+	character.Group = string(dfm.PC)
+
+	// Another player may not create characters for alice
+	if err := backend.CreateCharacter("bob", character); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for bob, got %v", err)
+	}
+
+	// Invalid characters are rejected
+	invalid := character
+	invalid.Name = "Alice/PC"
+	var validationErr *dfm.ValidationError
+	if err := backend.CreateCharacter("alice", invalid); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	if err := backend.CreateCharacter("alice", character); err != nil {
+		t.Fatalf("CreateCharacter failed: %v", err)
+	}
+
+	results, err := backend.GetUserCharacters("alice")
+	if err != nil {
+		t.Fatalf("GetUserCharacters failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != character.ID || len(results[0].Disciplines) != len(dfm.DefaultDisciplines) {
+		t.Errorf("Expected the created character, got %+v", results)
+	}
+}
+
+func TestSubscribeCharacterChanges(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	changes, unsubscribe := backend.SubscribeCharacterChanges()
+	defer unsubscribe()
+
+	character := dfm.NewCharacter(dfm.SpiritHuman)
+	character.Name = "Alice PC"
+	character.Player = "alice"
+	if err := backend.CreateCharacter("alice", character); err != nil {
+		t.Fatalf("CreateCharacter failed: %v", err)
+	}
+	character.FatePoint = 1
+	if _, err := backend.UpdateCharacter("alice", character); err != nil {
+		t.Fatalf("UpdateCharacter failed: %v", err)
+	}
+
+	for _, want := range []dfdb.ChangeType{dfdb.ChangeCreated, dfdb.ChangeUpdated} {
+		event := <-changes
+		if event.Type != want || event.CharacterID != character.ID {
+			t.Errorf("Expected %s event for %s, got %+v", want, character.ID, event)
+		}
+	}
+}
+
+func TestUpdateCharacter(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	character := dfm.Character{
+		ID:                  "550e8400-e29b-41d4-a716-446655440000",
+		Name:                "Alice PC",
+		Group:               string(dfm.PC),
+		Spirit:              string(dfm.SpiritHuman),
+		Player:              "alice",
+		Category:            "character",
+		Refresh:             3,
+		PhysicalStressLimit: 3,
+		MentalStressLimit:   3,
+	}
+	// Characters created directly are recorded in the history in the background,
+	// wait for that before the directory is removed
+	t.Cleanup(backend.history.Close)
+	if err := backend.provider.Create(character); err != nil {
+		t.Fatalf("Failed to create character: %v", err)
+	}
+	if err := backend.campaigns.CreateCampaign(dfm.Campaign{
+		ID:         "550e8400-e29b-41d4-a716-446655440200",
+		Name:       "Winter Court",
+		Gamemaster: "gm",
+		Players:    []string{"alice", "bob"},
+		Characters: []string{character.ID},
+	}); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
+	// The player saves a change
+	character.FatePoint = 2
+	character, err = backend.UpdateCharacter("alice", character)
+	if err != nil {
+		t.Fatalf("UpdateCharacter failed for the player: %v", err)
+	}
+
+	// The gamemaster of the campaign saves a change
+	character.PhysicalStressCurrent = 1
+	character, err = backend.UpdateCharacter("gm", character)
+	if err != nil {
+		t.Fatalf("UpdateCharacter failed for the gamemaster: %v", err)
+	}
+	if character.Revision != 2 {
+		t.Errorf("Expected the saved character at revision 2, got %d", character.Revision)
+	}
+
+	stored, err := backend.provider.Read(character.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if stored.FatePoint != 2 || stored.PhysicalStressCurrent != 1 {
+		t.Errorf("Expected changes to be stored, got %+v", stored)
+	}
+
+	// Another player in the campaign may see but not edit the character
+	character.FatePoint = 0
+	if _, err := backend.UpdateCharacter("bob", character); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for bob, got %v", err)
+	}
+
+	// Taking over the character by changing the player is not allowed either
+	character.Player = "bob"
+	if _, err := backend.UpdateCharacter("bob", character); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied when changing the player, got %v", err)
+	}
+
+	// Invalid characters are rejected before they are stored
+	character.Player = "alice"
+	character.PhysicalStressCurrent = 4
+	var validationErr *dfm.ValidationError
+	if _, err := backend.UpdateCharacter("alice", character); !errors.As(err, &validationErr) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+
+	// Changes based on an old revision are rejected
+	character.PhysicalStressCurrent = 2
+	character.Revision = 1
+	if _, err := backend.UpdateCharacter("alice", character); !errors.Is(err, dfdb.ErrStaleRevision) {
+		t.Errorf("Expected dfdb.ErrStaleRevision, got %v", err)
+	}
+
+	stored, err = backend.provider.Read(character.ID)
+	if err != nil {
+		t.Fatalf("Failed to read character: %v", err)
+	}
+	if stored.FatePoint != 2 || stored.PhysicalStressCurrent != 1 || stored.Player != "alice" {
+		t.Errorf("Rejected updates changed the stored character: %+v", stored)
+	}
+}
+
+func TestUpdateDocsCharacters(t *testing.T) {
+	// The character templates in docs/ have blank placeholder list entries
+	files, err := filepath.Glob(filepath.Join("..", "docs", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find the character templates: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", file, err)
+			}
+			var character dfm.Character
+			if err := json.Unmarshal(data, &character); err != nil {
+				t.Fatalf("Failed to parse %s: %v", file, err)
+			}
+			character.ID = dfm.NewID()
+			character.Name = "Template Character"
+			character.Player = "alice"
+
+			backend, err := newTestBackend(t.TempDir(), dfdb.NewMemoryProvider([]dfm.Character{character}))
+			if err != nil {
+				t.Fatalf("Failed to create backend: %v", err)
+			}
+
+			character.FatePoint++
+			saved, err := backend.UpdateCharacter("alice", character)
+			if err != nil {
+				t.Fatalf("UpdateCharacter failed: %v", err)
+			}
+			if err := saved.Validate(); err != nil {
+				t.Errorf("Saved character is not valid: %v", err)
+			}
+			stored, _ := backend.provider.Read(character.ID)
+			if stored.FatePoint != character.FatePoint || len(stored.Aliases)+len(stored.Tags)+len(stored.Collectives) != 0 {
+				t.Errorf("Expected the change stored without blank entries, got %+v", stored)
+			}
+		})
+	}
+}
+
+func TestCharacterHistoryAndRestore(t *testing.T) {
+	backend, err := NewDFDBBackendForTest(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	character := dfm.NewCharacter(dfm.SpiritHuman)
+	character.Name = "Alice PC"
+	character.Player = "alice"
+	character.FatePoint = 3
+	if err := backend.CreateCharacter("alice", character); err != nil {
+		t.Fatalf("CreateCharacter failed: %v", err)
+	}
+	character.FatePoint = 0
+	if _, err := backend.UpdateCharacter("alice", character); err != nil {
+		t.Fatalf("UpdateCharacter failed: %v", err)
+	}
+
+	entries, err := backend.GetCharacterHistory("alice", character.ID)
+	if err != nil {
+		t.Fatalf("GetCharacterHistory failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Username != "alice" || len(entries[1].Changes) != 1 {
+		t.Errorf("Unexpected history: %+v", entries)
+	}
+
+	// Other players may neither see the history nor restore
+	if _, err := backend.GetCharacterHistory("bob", character.ID); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for the history, got %v", err)
+	}
+	if _, err := backend.RestoreCharacter("bob", character.ID, 1); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for restoring, got %v", err)
+	}
+
+	restored, err := backend.RestoreCharacter("alice", character.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreCharacter failed: %v", err)
+	}
+	if restored.FatePoint != 3 {
+		t.Errorf("Expected the created version, got %+v", restored)
+	}
+
+	// Deleted characters can be found and restored by those who could edit them
+	if err := backend.history.Delete("alice", character.ID); err != nil {
+		t.Fatalf("Failed to delete character: %v", err)
+	}
+	if deleted, _ := backend.GetDeletedCharacters("bob"); len(deleted) != 0 {
+		t.Errorf("bob should not see deleted characters of alice: %+v", deleted)
+	}
+	deleted, err := backend.GetDeletedCharacters("alice")
+	if err != nil || len(deleted) != 1 || deleted[0].CharacterID != character.ID {
+		t.Fatalf("Expected the deleted character, got %+v, %v", deleted, err)
+	}
+	if _, err := backend.GetCharacterHistory("alice", character.ID); err != nil {
+		t.Errorf("History of a deleted character should stay visible: %v", err)
+	}
+	if _, err := backend.RestoreCharacter("alice", character.ID, deleted[0].Sequence); err != nil {
+		t.Fatalf("RestoreCharacter failed for a deleted character: %v", err)
+	}
+	if results, _ := backend.GetUserCharacters("alice"); len(results) != 1 || results[0].FatePoint != 3 {
+		t.Errorf("Expected the restored character, got %+v", results)
+	}
+}
+
 // NewDFDBBackendForTest creates a DFDBBackend for testing with a specific directory
 func NewDFDBBackendForTest(dir string) (*DFDBBackend, error) {
 	provider, err := dfdb.NewFsProvider(dir)
 	if err != nil {
 		return nil, err
 	}
-	return &DFDBBackend{provider: provider}, nil
+	return newTestBackend(dir, provider)
+}
+
+// newTestBackend creates a backend with the given character provider and the other data in dir
+func newTestBackend(dir string, provider dfdb.Provider) (*DFDBBackend, error) {
+	campaigns, err := dfdb.NewFsCampaignProvider(dir)
+	if err != nil {
+		return nil, err
+	}
+	trackers, err := dfdb.NewFsTrackerProvider(filepath.Join(dir, dfdb.SessionsDir))
+	if err != nil {
+		return nil, err
+	}
+	users, err := dfdb.NewFsUserProvider(filepath.Join(dir, dfdb.UsersFile))
+	if err != nil {
+		return nil, err
+	}
+	history, err := dfdb.NewFsHistoryProvider(filepath.Join(dir, dfdb.HistoryDir))
+	if err != nil {
+		return nil, err
+	}
+	return &DFDBBackend{
+		provider:  provider,
+		history:   dfdb.NewHistoryRecorder(provider, history),
+		trackers:  trackers,
+		campaigns: campaigns,
+		users:     users,
+	}, nil
 }
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the configuration file loaded when none is given
+const DefaultConfigFile = "dftui.yaml"
+
+// Config holds the configuration of the dftui server. It is loaded from a YAML or JSON file.
+type Config struct {
+	// Listen is the address the SSH server listens on, e.g. ":2222"
+	Listen string `yaml:"listen" json:"listen"`
+	// Host is the host name users connect to, shown in download commands
+	Host string `yaml:"host" json:"host"`
+	// HostKey is the path to the SSH host key, generated if missing. Empty for ~/.dftui/id_rsa
+	HostKey string `yaml:"hostKey" json:"hostKey"`
+	// Database is the directory of chronicles, campaigns, sessions and character histories
+	Database string `yaml:"database" json:"database"`
+	// Characters selects and configures the character provider
+	Characters dfdb.ProviderConfiguration `yaml:"characters" json:"characters"`
+	// Users is the path to the user registry. Empty for users.json in the database directory
+	Users string `yaml:"users" json:"users"`
+	// Logging configures the server log
+	Logging LoggingConfig `yaml:"logging" json:"logging"`
+}
+
+// LoggingConfig holds the configuration of the server log.
+type LoggingConfig struct {
+	// File is the path to the log file, appended to. Empty for standard error
+	File string `yaml:"file" json:"file"`
+	// Connections logs every SSH connection and its duration
+	Connections bool `yaml:"connections" json:"connections"`
+}
+
+// DefaultConfig returns the configuration used for settings missing from the configuration file.
+func DefaultConfig() Config {
+	config := baseConfig()
+	config.applyDefaults()
+	return config
+}
+
+// baseConfig returns the default configuration without the paths derived from the database directory.
+func baseConfig() Config {
+	return Config{
+		Listen:   ":2222",
+		Host:     "localhost",
+		Database: "db",
+		Characters: dfdb.ProviderConfiguration{
+			Provider: dfdb.FileSystemProvider,
+		},
+		Logging: LoggingConfig{Connections: true},
+	}
+}
+
+// LoadConfig reads a configuration file over the default configuration.
+// Files ending in .json are read as JSON, others as YAML. Unknown settings are rejected.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	config := baseConfig()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty YAML file has no documents and leaves the defaults
+		if err = decoder.Decode(&config); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config.applyDefaults()
+	return config, nil
+}
+
+// applyDefaults sets the paths that default to locations in the database directory.
+func (c *Config) applyDefaults() {
+	if c.Characters.Filesystem.Directory == "" {
+		c.Characters.Filesystem.Directory = filepath.Join(c.Database, "characters")
+	}
+	if c.Characters.Sqlite.Path == "" {
+		c.Characters.Sqlite.Path = filepath.Join(c.Database, "characters.db")
+	}
+	if c.Users == "" {
+		c.Users = filepath.Join(c.Database, dfdb.UsersFile)
+	}
+}
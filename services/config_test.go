@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.Listen != ":2222" || config.Host != "localhost" {
+		t.Errorf("Unexpected listen address %q and host %q", config.Listen, config.Host)
+	}
+	if config.Characters.Provider != dfdb.FileSystemProvider {
+		t.Errorf("Expected filesystem provider, got %q", config.Characters.Provider)
+	}
+	if config.Characters.Filesystem.Directory != filepath.Join("db", "characters") {
+		t.Errorf("Unexpected characters directory %q", config.Characters.Filesystem.Directory)
+	}
+	if config.Characters.Sqlite.Path != filepath.Join("db", "characters.db") {
+		t.Errorf("Unexpected characters database %q", config.Characters.Sqlite.Path)
+	}
+	if config.Users != filepath.Join("db", dfdb.UsersFile) {
+		t.Errorf("Unexpected user registry %q", config.Users)
+	}
+	if !config.Logging.Connections {
+		t.Error("Expected connections to be logged by default")
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	path := writeConfig(t, "dftui.yaml", `
+listen: ":3000"
+hostKey: /etc/dftui/host_key
+database: /srv/dftui
+characters:
+  provider: filesystem
+  filesystem:
+    directory: /srv/characters
+logging:
+  file: /var/log/dftui.log
+  connections: false
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if config.Listen != ":3000" || config.HostKey != "/etc/dftui/host_key" {
+		t.Errorf("Unexpected listen address %q and host key %q", config.Listen, config.HostKey)
+	}
+	// Settings missing from the file keep their defaults
+	if config.Host != "localhost" {
+		t.Errorf("Expected default host, got %q", config.Host)
+	}
+	if config.Characters.Filesystem.Directory != "/srv/characters" {
+		t.Errorf("Unexpected characters directory %q", config.Characters.Filesystem.Directory)
+	}
+	// The user registry defaults to the configured database directory
+	if config.Users != filepath.Join("/srv/dftui", dfdb.UsersFile) {
+		t.Errorf("Unexpected user registry %q", config.Users)
+	}
+	if config.Logging.File != "/var/log/dftui.log" || config.Logging.Connections {
+		t.Errorf("Unexpected logging config %+v", config.Logging)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeConfig(t, "dftui.json", `{"database": "data", "users": "users.json"}`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if config.Characters.Filesystem.Directory != filepath.Join("data", "characters") {
+		t.Errorf("Unexpected characters directory %q", config.Characters.Filesystem.Directory)
+	}
+	if config.Users != "users.json" {
+		t.Errorf("Unexpected user registry %q", config.Users)
+	}
+}
+
+func TestLoadConfigEmpty(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, "dftui.yaml", ""))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Errorf("Expected default config, got %+v", config)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected not exist error, got %v", err)
+	}
+
+	// Misspelled settings are rejected instead of silently ignored
+	if _, err := LoadConfig(writeConfig(t, "dftui.yaml", "listen: \":3000\"\nhostkey: key\n")); err == nil {
+		t.Error("Expected error for unknown YAML setting")
+	}
+	if _, err := LoadConfig(writeConfig(t, "dftui.json", `{"port": 3000}`)); err == nil {
+		t.Error("Expected error for unknown JSON setting")
+	}
+}
+
+func TestNewDFDBBackendConfig(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.Database = dir
+	config.Characters.Filesystem.Directory = filepath.Join(dir, "characters")
+	config.Characters.Sqlite.Path = filepath.Join(dir, "characters.db")
+	config.Users = filepath.Join(dir, "registry.json")
+
+	backend, err := NewDFDBBackend(config)
+	if err != nil {
+		t.Fatalf("NewDFDBBackend failed: %v", err)
+	}
+	defer backend.provider.(dfdb.Watcher).Close()
+
+	if _, err := os.Stat(config.Characters.Filesystem.Directory); err != nil {
+		t.Errorf("Expected characters directory to be created: %v", err)
+	}
+
+	// The SQLite provider is selected by the provider type
+	config.Characters.Provider = dfdb.SqliteDatabaseProvider
+	sqliteBackend, err := NewDFDBBackend(config)
+	if err != nil {
+		t.Fatalf("NewDFDBBackend failed for sqlite: %v", err)
+	}
+	defer sqliteBackend.provider.(*dfdb.SqliteProvider).Close()
+	if _, err := os.Stat(filepath.Join(dir, "characters.db")); err != nil {
+		t.Errorf("Expected characters database to be created: %v", err)
+	}
+
+	config.Characters.Provider = "cloud"
+	if _, err := NewDFDBBackend(config); !errors.Is(err, dfdb.ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+}
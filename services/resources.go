@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish/scp"
+	"github.com/hkionline/dftui/dflib/dfdb"
+)
+
+// ErrRecursiveDownload is returned when a client asks for a recursive SCP copy
+var ErrRecursiveDownload = errors.New("recursive downloads are not supported, download resource files one at a time")
+
+// ResourceHandler serves chronicle and campaign resource files over SCP.
+// It is read-only and each SSH user can only download the resources of the
+// chronicles and campaigns they take part in.
+type ResourceHandler struct {
+	backend Backend
+}
+
+var _ scp.CopyToClientHandler = (*ResourceHandler)(nil)
+
+// NewResourceHandler creates a read-only SCP handler for resource downloads.
+func NewResourceHandler(backend Backend) *ResourceHandler {
+	return &ResourceHandler{backend: backend}
+}
+
+// Glob returns the requested path as-is; server-side globbing is not supported.
+func (h *ResourceHandler) Glob(_ ssh.Session, pattern string) ([]string, error) {
+	return []string{strings.TrimPrefix(path.Clean("/"+pattern), "/")}, nil
+}
+
+// WalkDir rejects recursive copies.
+func (h *ResourceHandler) WalkDir(_ ssh.Session, _ string, _ fs.WalkDirFunc) error {
+	return ErrRecursiveDownload
+}
+
+// NewDirEntry rejects directory copies.
+func (h *ResourceHandler) NewDirEntry(_ ssh.Session, _ string) (*scp.DirEntry, error) {
+	return nil, ErrRecursiveDownload
+}
+
+// NewFileEntry opens a resource file for the connected user.
+func (h *ResourceHandler) NewFileEntry(s ssh.Session, resourcePath string) (*scp.FileEntry, func() error, error) {
+	file, err := h.backend.OpenUserResource(s.User(), resourcePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat resource %s: %w", resourcePath, err)
+	}
+
+	return &scp.FileEntry{
+		Name:     info.Name(),
+		Filepath: resourcePath,
+		Mode:     info.Mode().Perm(),
+		Size:     info.Size(),
+		Mtime:    info.ModTime().Unix(),
+		Atime:    info.ModTime().Unix(),
+		Reader:   file,
+	}, func() error {
+		// The file is closed after it has been written to the client
+		waitForAcks(s)
+		return file.Close()
+	}, nil
+}
+
+// scpAcks is the number of acknowledgements an SCP client sends when receiving
+// a single file: one when it starts and one each after the time, file and data records
+const scpAcks = 4
+
+// waitForAcks reads the acknowledgements of a single file transfer from the client.
+// Closing the SSH channel before the client has sent its last acknowledgement
+// makes the client fail with "lost connection" even though it got the file.
+// It returns early if the client disconnects.
+func waitForAcks(r io.Reader) {
+	acks := make([]byte, scpAcks)
+	_, _ = io.ReadFull(r, acks)
+}
+
+// ChronicleResourcePath returns the download path of a chronicle resource file,
+// e.g. "chronicles/{uuid}/rules.pdf"
+func ChronicleResourcePath(chronicleID, name string) string {
+	return path.Join(dfdb.ChroniclesDir, chronicleID, name)
+}
+
+// CampaignResourcePath returns the download path of a campaign resource file,
+// e.g. "campaigns/{uuid}/handout.pdf"
+func CampaignResourcePath(campaignID, name string) string {
+	return path.Join(dfdb.CampaignsDir, campaignID, name)
+}
+
+// OpenUserResource opens a resource file by its download path.
+// Only resources of chronicles and campaigns the user may see can be opened;
+// anything else is reported as not found so the existence of other users'
+// resources is not revealed.
+func (b *DFDBBackend) OpenUserResource(username, resourcePath string) (fs.File, error) {
+	access, err := b.userAccess(username)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(resourcePath, "/"), "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: %s", dfdb.ErrResourceNotFound, resourcePath)
+	}
+	kind, id, name := parts[0], parts[1], parts[2]
+
+	var file fs.File
+	switch kind {
+	case dfdb.ChroniclesDir:
+		chronicle, readErr := b.campaigns.ReadChronicle(id)
+		if readErr != nil || !access.CanSeeChronicle(chronicle) {
+			return nil, fmt.Errorf("%w: %s", dfdb.ErrResourceNotFound, resourcePath)
+		}
+		file, err = b.campaigns.OpenChronicleResource(id, name)
+	case dfdb.CampaignsDir:
+		campaign, readErr := b.campaigns.ReadCampaign(id)
+		if readErr != nil || !access.CanSeeCampaign(campaign) {
+			return nil, fmt.Errorf("%w: %s", dfdb.ErrResourceNotFound, resourcePath)
+		}
+		file, err = b.campaigns.OpenCampaignResource(id, name)
+	default:
+		return nil, fmt.Errorf("%w: %s", dfdb.ErrResourceNotFound, resourcePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resource %s: %w", resourcePath, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestOpenUserResource(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewDFDBBackendForTest(dir)
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+
+	chronicle := dfm.Chronicle{ID: "550e8400-e29b-41d4-a716-446655440100", Name: "Helsinki by Night", Gamemaster: "gm", Players: []string{"alice"}}
+	campaign := dfm.Campaign{ID: "550e8400-e29b-41d4-a716-446655440200", ChronicleID: chronicle.ID, Name: "Winter Court", Gamemaster: "gm", Players: []string{"bob"}}
+	if err := backend.campaigns.CreateChronicle(chronicle); err != nil {
+		t.Fatalf("Failed to create chronicle: %v", err)
+	}
+	if err := backend.campaigns.CreateCampaign(campaign); err != nil {
+		t.Fatalf("Failed to create campaign: %v", err)
+	}
+
+	chronicleResources := filepath.Join(dir, dfdb.ChroniclesDir, chronicle.ID, dfdb.ResourcesDir)
+	campaignResources := filepath.Join(dir, dfdb.CampaignsDir, campaign.ID, dfdb.ResourcesDir)
+	os.MkdirAll(chronicleResources, 0755)
+	os.MkdirAll(campaignResources, 0755)
+	os.WriteFile(filepath.Join(chronicleResources, "rules.pdf"), []byte("%PDF-1.7"), 0644)
+	os.WriteFile(filepath.Join(campaignResources, "handout.txt"), []byte("Clue"), 0644)
+
+	resources, err := backend.GetChronicleResources("alice", chronicle.ID)
+	if err != nil || len(resources) != 1 || resources[0].Name != "rules.pdf" {
+		t.Errorf("Unexpected chronicle resources %+v, err %v", resources, err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		path     string
+		want     string // file content, empty if access is denied
+	}{
+		{"Player downloads chronicle resource", "alice", ChronicleResourcePath(chronicle.ID, "rules.pdf"), "%PDF-1.7"},
+		{"Gamemaster downloads campaign resource", "gm", CampaignResourcePath(campaign.ID, "handout.txt"), "Clue"},
+		{"Leading slash is accepted", "bob", "/" + CampaignResourcePath(campaign.ID, "handout.txt"), "Clue"},
+		{"Non-member is denied", "alice", CampaignResourcePath(campaign.ID, "handout.txt"), ""},
+		{"Unknown user is denied", "mallory", ChronicleResourcePath(chronicle.ID, "rules.pdf"), ""},
+		{"Missing file", "alice", ChronicleResourcePath(chronicle.ID, "missing.pdf"), ""},
+		{"Path traversal", "alice", ChronicleResourcePath(chronicle.ID, "") + "/../../characters/x.json", ""},
+		{"Chronicle README is not a resource", "alice", "chronicles/" + chronicle.ID + "/README.md", ""},
+		{"Unknown kind", "alice", "characters/" + chronicle.ID + "/rules.pdf", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := backend.OpenUserResource(tt.username, tt.path)
+			if tt.want == "" {
+				if !errors.Is(err, dfdb.ErrResourceNotFound) {
+					t.Errorf("Expected ErrResourceNotFound, got %v", err)
+				}
+				if file != nil {
+					file.Close()
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenUserResource failed: %v", err)
+			}
+			defer file.Close()
+
+			data, err := io.ReadAll(file)
+			if err != nil || string(data) != tt.want {
+				t.Errorf("Expected content %q, got %q (err %v)", tt.want, data, err)
+			}
+		})
+	}
+}
+
+func TestResourceHandlerGlobCleansPath(t *testing.T) {
+	handler := NewResourceHandler(nil)
+
+	tests := map[string]string{
+		"chronicles/id/rules.pdf":       "chronicles/id/rules.pdf",
+		"/chronicles/id/rules.pdf":      "chronicles/id/rules.pdf",
+		"../chronicles/id/../rules.pdf": "chronicles/rules.pdf",
+		"chronicles/id/*.pdf":           "chronicles/id/*.pdf",
+	}
+	for pattern, want := range tests {
+		matches, err := handler.Glob(nil, pattern)
+		if err != nil || len(matches) != 1 || matches[0] != want {
+			t.Errorf("Glob(%q) = %v, %v; want [%s]", pattern, matches, err, want)
+		}
+	}
+
+	if err := handler.WalkDir(nil, "chronicles", nil); !errors.Is(err, ErrRecursiveDownload) {
+		t.Errorf("Expected ErrRecursiveDownload, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfdice"
+)
+
+// sessionEventBuffer is the number of events buffered per client before new events are dropped
+const sessionEventBuffer = 64
+
+// SessionEventType identifies the kind of a session event
+type SessionEventType string
+
+const (
+	// SessionEventJoin is sent when a user joins a game session
+	SessionEventJoin SessionEventType = "join"
+	// SessionEventLeave is sent when a user leaves a game session or disconnects
+	SessionEventLeave SessionEventType = "leave"
+	// SessionEventRoll is sent when a user rolls dice in a game session
+	SessionEventRoll SessionEventType = "roll"
+	// SessionEventTurn is sent when a user passes the turn in a game session
+	SessionEventTurn SessionEventType = "turn"
+)
+
+// SessionEvent is an event shared between all users in the same game session.
+type SessionEvent struct {
+	// Type is the kind of the event
+	Type SessionEventType
+	// SessionID identifies the game session the event belongs to
+	SessionID string
+	// Username is the user who caused the event
+	Username string
+	// Character is the name of the character rolling or taking the turn, if any
+	Character string
+	// Roll is the dice roll result for roll events
+	Roll dfdice.Result
+	// Time is when the event happened
+	Time time.Time
+}
+
+// ActiveSession is a game session with at least one connected participant.
+type ActiveSession struct {
+	// ID identifies the game session
+	ID string
+	// Participants are the sorted usernames of the users in the session
+	Participants []string
+}
+
+// SessionHub relays session-mode events between the connected SSH users.
+// Each connected user gets a SessionClient; events published by a client
+// are delivered to every other client in the same game session.
+type SessionHub struct {
+	mu       sync.RWMutex
+	clients  map[*SessionClient]struct{}            // all connected clients
+	sessions map[string]map[*SessionClient]struct{} // clients by game session ID
+}
+
+// NewSessionHub creates a new, empty session hub.
+func NewSessionHub() *SessionHub {
+	return &SessionHub{
+		clients:  make(map[*SessionClient]struct{}),
+		sessions: make(map[string]map[*SessionClient]struct{}),
+	}
+}
+
+// NewClient creates a client for a connected user.
+// The client is not part of any game session until Join is called.
+func (h *SessionHub) NewClient(username string) *SessionClient {
+	client := &SessionClient{
+		hub:      h,
+		username: username,
+		events:   make(chan SessionEvent, sessionEventBuffer),
+		presence: make(chan struct{}, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[client] = struct{}{}
+	h.notifyPresence()
+	return client
+}
+
+// ActiveSessions returns the game sessions that have connected participants, sorted by ID.
+func (h *SessionHub) ActiveSessions() []ActiveSession {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	active := make([]ActiveSession, 0, len(h.sessions))
+	for id := range h.sessions {
+		active = append(active, ActiveSession{ID: id, Participants: h.participants(id)})
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].ID < active[j].ID })
+	return active
+}
+
+// Participants returns the sorted usernames of the clients in a game session.
+// A user connected more than once is listed once.
+func (h *SessionHub) Participants(sessionID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.participants(sessionID)
+}
+
+// participants returns the sorted, de-duplicated usernames in a game session.
+// The caller must hold h.mu.
+func (h *SessionHub) participants(sessionID string) []string {
+	seen := make(map[string]bool)
+	participants := []string{}
+	for client := range h.sessions[sessionID] {
+		if !seen[client.username] {
+			seen[client.username] = true
+			participants = append(participants, client.username)
+		}
+	}
+	sort.Strings(participants)
+	return participants
+}
+
+// broadcast delivers an event to every client in the event's session except the sender.
+// Delivery never blocks: if a client's buffer is full, the event is dropped for that client.
+// The caller must hold h.mu.
+func (h *SessionHub) broadcast(sender *SessionClient, event SessionEvent) {
+	for client := range h.sessions[event.SessionID] {
+		if client == sender {
+			continue
+		}
+		select {
+		case client.events <- event:
+		default:
+			// Slow client, drop the event rather than block the others
+		}
+	}
+}
+
+// notifyPresence signals every connected client that users have connected,
+// disconnected, joined or left a game session. Signals are coalesced so a
+// client that has not yet handled the previous one is not signalled again.
+// The caller must hold h.mu.
+func (h *SessionHub) notifyPresence() {
+	for client := range h.clients {
+		select {
+		case client.presence <- struct{}{}:
+		default:
+			// Already signalled
+		}
+	}
+}
+
+// leave removes a client from its current game session and notifies the others.
+// The caller must hold h.mu for writing.
+func (h *SessionHub) leave(client *SessionClient) {
+	if client.sessionID == "" {
+		return
+	}
+
+	sessionID := client.sessionID
+	delete(h.sessions[sessionID], client)
+	if len(h.sessions[sessionID]) == 0 {
+		delete(h.sessions, sessionID)
+	}
+	client.sessionID = ""
+
+	h.broadcast(client, SessionEvent{
+		Type:      SessionEventLeave,
+		SessionID: sessionID,
+		Username:  client.username,
+		Time:      time.Now(),
+	})
+	h.notifyPresence()
+}
+
+// SessionClient is a connected user's handle to the session hub.
+// It is safe for concurrent use.
+type SessionClient struct {
+	hub       *SessionHub
+	username  string
+	sessionID string // current game session, guarded by hub.mu
+	closed    bool   // guarded by hub.mu
+	events    chan SessionEvent
+	presence  chan struct{}
+}
+
+// Events returns the channel of events from other users in the current game session.
+// The channel is closed when the client is closed.
+func (c *SessionClient) Events() <-chan SessionEvent {
+	return c.events
+}
+
+// PresenceChanged returns a channel that receives a signal whenever users connect,
+// disconnect, join or leave game sessions anywhere on the server.
+// The channel is closed when the client is closed.
+func (c *SessionClient) PresenceChanged() <-chan struct{} {
+	return c.presence
+}
+
+// ActiveSessions returns the game sessions that currently have connected participants.
+func (c *SessionClient) ActiveSessions() []ActiveSession {
+	return c.hub.ActiveSessions()
+}
+
+// SessionID returns the current game session ID, or an empty string in single-mode.
+func (c *SessionClient) SessionID() string {
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+	return c.sessionID
+}
+
+// Participants returns the usernames in the client's current game session.
+func (c *SessionClient) Participants() []string {
+	return c.hub.Participants(c.SessionID())
+}
+
+// Join moves the client into a game session, leaving the current one first.
+func (c *SessionClient) Join(sessionID string) {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c.closed || sessionID == "" || c.sessionID == sessionID {
+		return
+	}
+
+	h.leave(c)
+
+	if h.sessions[sessionID] == nil {
+		h.sessions[sessionID] = make(map[*SessionClient]struct{})
+	}
+	h.sessions[sessionID][c] = struct{}{}
+	c.sessionID = sessionID
+
+	h.broadcast(c, SessionEvent{
+		Type:      SessionEventJoin,
+		SessionID: sessionID,
+		Username:  c.username,
+		Time:      time.Now(),
+	})
+	h.notifyPresence()
+}
+
+// Leave removes the client from its current game session.
+func (c *SessionClient) Leave() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hub.leave(c)
+}
+
+// Publish sends an event to the other users in the client's current game session.
+// SessionID and Username are filled in from the client. It does nothing in single-mode.
+func (c *SessionClient) Publish(event SessionEvent) {
+	h := c.hub
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if c.closed || c.sessionID == "" {
+		return
+	}
+
+	event.SessionID = c.sessionID
+	event.Username = c.username
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	h.broadcast(c, event)
+}
+
+// Close leaves the current game session and closes the events channel.
+// It should be called when the SSH session ends. Close is idempotent.
+func (c *SessionClient) Close() {
+	h := c.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+	h.leave(c)
+	delete(h.clients, c)
+	c.closed = true
+	close(c.events)
+	close(c.presence)
+	h.notifyPresence()
+}
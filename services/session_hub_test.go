@@ -0,0 +1,279 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hkionline/dftui/dflib/dfdice"
+)
+
+// receiveEvent waits for the next event from a client or fails the test
+func receiveEvent(t *testing.T, client *SessionClient) SessionEvent {
+	t.Helper()
+	select {
+	case event, ok := <-client.Events():
+		if !ok {
+			t.Fatal("Events channel closed unexpectedly")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	return SessionEvent{}
+}
+
+// expectNoEvent fails the test if the client has a pending event
+func expectNoEvent(t *testing.T, client *SessionClient) {
+	t.Helper()
+	select {
+	case event := <-client.Events():
+		t.Fatalf("Unexpected event: %+v", event)
+	default:
+	}
+}
+
+func TestSessionHubBroadcastsRollsWithinSession(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	bob := hub.NewClient("bob")
+	carol := hub.NewClient("carol")
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	alice.Join("session-1")
+	bob.Join("session-1")
+	carol.Join("session-2")
+
+	// Alice is told that Bob joined
+	if event := receiveEvent(t, alice); event.Type != SessionEventJoin || event.Username != "bob" {
+		t.Errorf("Expected join event from bob, got %+v", event)
+	}
+
+	roll := dfdice.Result{Dice: [dfdice.DiceCount]dfdice.Face{dfdice.Plus, dfdice.Plus, dfdice.Blank, dfdice.Minus}, Skill: "fight", Bonus: 2}
+	alice.Publish(SessionEvent{Type: SessionEventRoll, Character: "Victor", Roll: roll})
+
+	event := receiveEvent(t, bob)
+	if event.Type != SessionEventRoll {
+		t.Fatalf("Expected roll event, got %s", event.Type)
+	}
+	if event.Username != "alice" || event.SessionID != "session-1" || event.Character != "Victor" {
+		t.Errorf("Unexpected roll event metadata: %+v", event)
+	}
+	if event.Roll.Total() != 3 {
+		t.Errorf("Roll total mismatch: got %d, want 3", event.Roll.Total())
+	}
+
+	// Sender and other sessions do not receive the roll
+	expectNoEvent(t, alice)
+	expectNoEvent(t, carol)
+}
+
+func TestSessionHubPublishInSingleModeIsIgnored(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	bob := hub.NewClient("bob")
+	defer alice.Close()
+	defer bob.Close()
+
+	bob.Join("session-1")
+	alice.Publish(SessionEvent{Type: SessionEventRoll})
+
+	expectNoEvent(t, bob)
+}
+
+func TestSessionHubJoinAndLeave(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	bob := hub.NewClient("bob")
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Join("session-1")
+	bob.Join("session-1")
+	receiveEvent(t, alice) // bob joined
+
+	participants := hub.Participants("session-1")
+	if len(participants) != 2 || participants[0] != "alice" || participants[1] != "bob" {
+		t.Errorf("Unexpected participants: %v", participants)
+	}
+
+	// Joining another session leaves the current one
+	bob.Join("session-2")
+	if event := receiveEvent(t, alice); event.Type != SessionEventLeave || event.Username != "bob" {
+		t.Errorf("Expected leave event from bob, got %+v", event)
+	}
+	if bob.SessionID() != "session-2" {
+		t.Errorf("SessionID mismatch: got %s, want session-2", bob.SessionID())
+	}
+
+	alice.Leave()
+	if alice.SessionID() != "" {
+		t.Errorf("SessionID should be empty after leave, got %s", alice.SessionID())
+	}
+	if participants := hub.Participants("session-1"); len(participants) != 0 {
+		t.Errorf("Expected no participants, got %v", participants)
+	}
+}
+
+func TestSessionHubCloseNotifiesAndClosesChannel(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	bob := hub.NewClient("bob")
+	defer alice.Close()
+
+	alice.Join("session-1")
+	bob.Join("session-1")
+	receiveEvent(t, alice) // bob joined
+
+	bob.Close()
+	bob.Close() // Close is idempotent
+
+	if event := receiveEvent(t, alice); event.Type != SessionEventLeave || event.Username != "bob" {
+		t.Errorf("Expected leave event from bob, got %+v", event)
+	}
+	if _, ok := <-bob.Events(); ok {
+		t.Error("Events channel should be closed")
+	}
+
+	// Closed clients cannot rejoin
+	bob.Join("session-1")
+	if participants := hub.Participants("session-1"); len(participants) != 1 {
+		t.Errorf("Expected only alice, got %v", participants)
+	}
+}
+
+func TestSessionHubSlowClientDoesNotBlock(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	slow := hub.NewClient("slow")
+	defer alice.Close()
+	defer slow.Close()
+
+	alice.Join("session-1")
+	slow.Join("session-1")
+
+	done := make(chan struct{})
+	go func() {
+		// Publish far more events than the buffer holds without anyone reading
+		for i := 0; i < sessionEventBuffer*4; i++ {
+			alice.Publish(SessionEvent{Type: SessionEventRoll})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow client")
+	}
+
+	if len(slow.Events()) != sessionEventBuffer {
+		t.Errorf("Expected full buffer of %d events, got %d", sessionEventBuffer, len(slow.Events()))
+	}
+}
+
+func TestSessionHubConcurrentAccess(t *testing.T) {
+	hub := NewSessionHub()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := hub.NewClient("user")
+			client.Join("session-1")
+			client.Publish(SessionEvent{Type: SessionEventRoll})
+			hub.Participants("session-1")
+			client.Close()
+		}()
+	}
+
+	wg.Wait()
+	// If we get here without deadlock or race, test passes
+}
+
+// expectPresence waits for a presence signal from a client or fails the test
+func expectPresence(t *testing.T, client *SessionClient) {
+	t.Helper()
+	select {
+	case <-client.PresenceChanged():
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for presence signal")
+	}
+}
+
+func TestSessionHubActiveSessions(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	bob := hub.NewClient("bob")
+	carol := hub.NewClient("carol")
+	defer alice.Close()
+	defer bob.Close()
+
+	if active := hub.ActiveSessions(); len(active) != 0 {
+		t.Fatalf("Expected no active sessions, got %+v", active)
+	}
+
+	bob.Join("session-2")
+	alice.Join("session-1")
+	carol.Join("session-1")
+
+	active := alice.ActiveSessions()
+	if len(active) != 2 {
+		t.Fatalf("Expected 2 active sessions, got %+v", active)
+	}
+	if active[0].ID != "session-1" || len(active[0].Participants) != 2 ||
+		active[0].Participants[0] != "alice" || active[0].Participants[1] != "carol" {
+		t.Errorf("Unexpected first session: %+v", active[0])
+	}
+	if active[1].ID != "session-2" || len(active[1].Participants) != 1 {
+		t.Errorf("Unexpected second session: %+v", active[1])
+	}
+
+	// Sessions disappear when their last participant disconnects
+	bob.Leave()
+	carol.Close()
+	active = hub.ActiveSessions()
+	if len(active) != 1 || active[0].ID != "session-1" || len(active[0].Participants) != 1 {
+		t.Errorf("Expected only alice in session-1, got %+v", active)
+	}
+}
+
+func TestSessionHubPresenceSignals(t *testing.T) {
+	hub := NewSessionHub()
+	alice := hub.NewClient("alice")
+	expectPresence(t, alice)
+
+	// Alice is signalled when others connect, join, leave and disconnect,
+	// even if she is not in their game session
+	bob := hub.NewClient("bob")
+	expectPresence(t, alice)
+
+	bob.Join("session-1")
+	expectPresence(t, alice)
+
+	bob.Leave()
+	expectPresence(t, alice)
+
+	bob.Close()
+	expectPresence(t, alice)
+
+	// Signals are coalesced
+	carol := hub.NewClient("carol")
+	carol.Join("session-1")
+	carol.Leave()
+	expectPresence(t, alice)
+	select {
+	case <-alice.PresenceChanged():
+		t.Error("Expected signals to be coalesced")
+	default:
+	}
+
+	// Closing a client closes its presence channel
+	carol.Close()
+	alice.Close()
+	for range alice.PresenceChanged() {
+	}
+}
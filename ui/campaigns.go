@@ -0,0 +1,391 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfdice"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// campaignHeaderLines is the number of lines above a document in the campaign document views
+const campaignHeaderLines = 3
+
+// campaignsLoadedMsg is sent when campaigns are loaded from backend
+type campaignsLoadedMsg struct {
+	campaigns []dfm.Campaign
+	err       error
+}
+
+// campaignSessionsLoadedMsg is sent when a campaign's sessions are loaded from backend
+type campaignSessionsLoadedMsg struct {
+	campaignID string
+	sessions   []dfm.Session
+	err        error
+}
+
+// campaignDocumentLoadedMsg is sent when a campaign README or session document is loaded from backend
+type campaignDocumentLoadedMsg struct {
+	documentID   string // ID of the campaign or session the document belongs to
+	markdown     string
+	err          error
+	resources    []dfm.Resource // Downloadable files, for campaign READMEs
+	resourcesErr error
+}
+
+// loadCampaigns loads the user's campaigns from the backend
+func loadCampaigns(username string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		campaigns, err := backend.GetUserCampaigns(username)
+		return campaignsLoadedMsg{
+			campaigns: campaigns,
+			err:       err,
+		}
+	}
+}
+
+// loadCampaignSessions loads the sessions of a campaign from the backend
+func loadCampaignSessions(username, campaignID string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := backend.GetCampaignSessions(username, campaignID)
+		return campaignSessionsLoadedMsg{
+			campaignID: campaignID,
+			sessions:   sessions,
+			err:        err,
+		}
+	}
+}
+
+// loadCampaignReadme loads a campaign's README and downloadable resources from the backend
+func loadCampaignReadme(username, campaignID string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		readme, err := backend.GetCampaignReadme(username, campaignID)
+		if errors.Is(err, dfdb.ErrDocumentNotFound) {
+			readme, err = "*This campaign has no README.*", nil
+		}
+		resources, resourcesErr := backend.GetCampaignResources(username, campaignID)
+		return campaignDocumentLoadedMsg{
+			documentID:   campaignID,
+			markdown:     readme,
+			err:          err,
+			resources:    resources,
+			resourcesErr: resourcesErr,
+		}
+	}
+}
+
+// loadSessionDocument loads a session's notes and Fate tracker log from the backend
+// and combines them into a single Markdown document
+func loadSessionDocument(username string, session dfm.Session, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		notes, err := backend.GetSessionNotes(username, session.ID)
+		if errors.Is(err, dfdb.ErrDocumentNotFound) {
+			notes, err = "*No notes for this session.*", nil
+		}
+		if err != nil {
+			return campaignDocumentLoadedMsg{documentID: session.ID, err: err}
+		}
+
+		tracker, err := backend.GetSessionLog(username, session.ID)
+		if errors.Is(err, dfdb.ErrTrackerNotFound) {
+			tracker, err = dfm.SessionTracker{SessionID: session.ID}, nil
+		}
+		if err != nil {
+			return campaignDocumentLoadedMsg{documentID: session.ID, err: err}
+		}
+
+		return campaignDocumentLoadedMsg{
+			documentID: session.ID,
+			markdown:   sessionDocument(notes, tracker),
+		}
+	}
+}
+
+// sessionDocument combines session notes and the Fate tracker log into one Markdown document
+func sessionDocument(notes string, tracker dfm.SessionTracker) string {
+	var b strings.Builder
+	b.WriteString(notes)
+	b.WriteString("\n\n## Fate Tracker Log\n\n")
+
+	if len(tracker.Entries) == 0 {
+		b.WriteString("*No Fate tracker log stored for this session.*\n")
+		return b.String()
+	}
+
+	b.WriteString("| Time | Who | Event | Result |\n")
+	b.WriteString("|------|-----|-------|--------|\n")
+	for _, entry := range tracker.Entries {
+		who := entry.Username
+		if entry.Character != "" {
+			who = fmt.Sprintf("%s (%s)", entry.Character, entry.Username)
+		}
+
+		var event, result string
+		switch entry.Type {
+		case dfm.TrackerEntryTurn:
+			event = "Turn"
+			result = entry.Turn
+		default:
+			event = "Roll " + formatTrackerDice(entry.Dice)
+			if entry.Skill != "" {
+				event += fmt.Sprintf(" %s %+d", strings.Title(entry.Skill), entry.SkillRating)
+			}
+			if entry.Modifier != 0 {
+				event += fmt.Sprintf(" modifier %+d", entry.Modifier)
+			}
+			for _, invoke := range entry.Invokes {
+				event += fmt.Sprintf(" invoke %s %+d", invoke.Aspect, invoke.Bonus)
+			}
+			result = fmt.Sprintf("%+d %s", entry.Total, dfdice.LadderName(entry.Total))
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			entry.Time.Format("15:04:05"),
+			escapeTableCell(who),
+			escapeTableCell(event),
+			escapeTableCell(result))
+	}
+	return b.String()
+}
+
+// formatTrackerDice formats logged dice faces, e.g. "[+][-][ ][+]"
+func formatTrackerDice(dice []int) string {
+	var b strings.Builder
+	for _, face := range dice {
+		switch {
+		case face > 0:
+			b.WriteString("[+]")
+		case face < 0:
+			b.WriteString("[-]")
+		default:
+			b.WriteString("[ ]")
+		}
+	}
+	return b.String()
+}
+
+// escapeTableCell escapes characters that would break a Markdown table cell
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// updateCampaigns handles key presses specific to the Campaigns tab.
+// It returns false if the key was not handled so global keys keep working.
+func (m Model) updateCampaigns(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch m.campaignViewMode {
+	case CampaignViewReadme, CampaignViewSession:
+		if msg.String() == "esc" {
+			// Return to the campaign's README and session list
+			m.campaignViewMode = CampaignViewDetail
+			m.selectedSession = nil
+			return m, nil, true
+		}
+
+		// Scroll the document
+		vp, handled := updateDocumentViewport(m.campaignViewport, msg)
+		m.campaignViewport = vp
+		return m, nil, handled
+
+	case CampaignViewDetail:
+		switch msg.String() {
+		case "esc":
+			// Return to campaign list
+			m.campaignViewMode = CampaignViewList
+			m.selectedCampaign = nil
+			m.campaignSessions = nil
+			return m, nil, true
+
+		case "up":
+			if m.selectedCampaignItem > 0 {
+				m.selectedCampaignItem--
+			}
+			return m, nil, true
+
+		case "down":
+			// Item 0 is the README, followed by the sessions
+			if m.selectedCampaignItem < len(m.campaignSessions) {
+				m.selectedCampaignItem++
+			}
+			return m, nil, true
+
+		case "enter":
+			m.campaignDocument = ""
+			m.campaignDocumentErr = nil
+			m.campaignResources = nil
+			m.campaignResourcesErr = nil
+			m.campaignViewport.SetContent("Loading...")
+			m.campaignViewport.GotoTop()
+
+			if m.selectedCampaignItem == 0 {
+				m.campaignViewMode = CampaignViewReadme
+				return m, loadCampaignReadme(m.username, m.selectedCampaign.ID, m.backend), true
+			}
+
+			m.selectedSession = &m.campaignSessions[m.selectedCampaignItem-1]
+			m.campaignViewMode = CampaignViewSession
+			return m, loadSessionDocument(m.username, *m.selectedSession, m.backend), true
+		}
+		return m, nil, false
+	}
+
+	// List view
+	switch msg.String() {
+	case "up":
+		if m.selectedCampaignIndex > 0 {
+			m.selectedCampaignIndex--
+		}
+		return m, nil, true
+
+	case "down":
+		if m.selectedCampaignIndex < len(m.campaigns)-1 {
+			m.selectedCampaignIndex++
+		}
+		return m, nil, true
+
+	case "enter":
+		// Open the campaign and load its sessions
+		if m.selectedCampaignIndex >= 0 && m.selectedCampaignIndex < len(m.campaigns) {
+			m.selectedCampaign = &m.campaigns[m.selectedCampaignIndex]
+			m.campaignViewMode = CampaignViewDetail
+			m.campaignSessions = nil
+			m.campaignSessionsErr = nil
+			m.selectedCampaignItem = 0
+			return m, loadCampaignSessions(m.username, m.selectedCampaign.ID, m.backend), true
+		}
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// openCampaignDocumentID returns the ID of the campaign or session whose document is open
+func (m Model) openCampaignDocumentID() string {
+	switch {
+	case m.campaignViewMode == CampaignViewReadme && m.selectedCampaign != nil:
+		return m.selectedCampaign.ID
+	case m.campaignViewMode == CampaignViewSession && m.selectedSession != nil:
+		return m.selectedSession.ID
+	}
+	return ""
+}
+
+// setCampaignDocument renders the loaded document into the campaign viewport
+func (m *Model) setCampaignDocument() {
+	width, height := m.documentSize(campaignHeaderLines)
+	m.campaignViewport.Width = width
+	m.campaignViewport.Height = height
+
+	if m.campaignDocumentErr != nil {
+		m.campaignViewport.SetContent(fmt.Sprintf("Error loading document: %v", m.campaignDocumentErr))
+		return
+	}
+
+	document := m.campaignDocument
+	if m.campaignViewMode == CampaignViewReadme {
+		campaignID := m.selectedCampaign.ID
+		document += m.downloadsMarkdown(m.campaignResources, m.campaignResourcesErr, func(name string) string {
+			return services.CampaignResourcePath(campaignID, name)
+		})
+	}
+	m.campaignViewport.SetContent(renderMarkdown(document, width))
+}
+
+// renderCampaignsTab renders the Campaigns tab content
+func (m Model) renderCampaignsTab() string {
+	switch m.campaignViewMode {
+	case CampaignViewDetail:
+		return m.renderCampaignDetail()
+	case CampaignViewReadme, CampaignViewSession:
+		return m.renderCampaignDocument()
+	}
+
+	// List view
+	if m.campaignsErr != nil {
+		return fmt.Sprintf("Error loading campaigns: %v", m.campaignsErr)
+	}
+
+	if len(m.campaigns) == 0 {
+		return "No campaigns found"
+	}
+
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Campaigns for %s:", m.username)))
+	lines = append(lines, "")
+
+	for i, campaign := range m.campaigns {
+		lines = append(lines, renderListItem(campaign.Name, campaign.Description, m.selectedCampaignIndex == i))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderCampaignDetail renders the selected campaign with its README entry and session list
+func (m Model) renderCampaignDetail() string {
+	if m.selectedCampaign == nil {
+		return "No campaign selected"
+	}
+
+	campaign := m.selectedCampaign
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")).Render(campaign.Name))
+	lines = append(lines, hintStyle.Render(fmt.Sprintf("Gamemaster: %s", campaign.Gamemaster)))
+	if len(campaign.Players) > 0 {
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("Players: %s", strings.Join(campaign.Players, ", "))))
+	}
+	if campaign.Description != "" {
+		lines = append(lines, "")
+		lines = append(lines, campaign.Description)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, renderListItem("README", "", m.selectedCampaignItem == 0))
+
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Sessions:"))
+	switch {
+	case m.campaignSessionsErr != nil:
+		lines = append(lines, fmt.Sprintf("Error loading sessions: %v", m.campaignSessionsErr))
+	case len(m.campaignSessions) == 0:
+		lines = append(lines, hintStyle.Render("  No sessions played yet"))
+	default:
+		for i, session := range m.campaignSessions {
+			played := ""
+			if !session.PlayedAt.IsZero() {
+				played = session.PlayedAt.Format("2006-01-02")
+			}
+			lines = append(lines, renderListItem(session.Name, played, m.selectedCampaignItem == i+1))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderCampaignDocument renders the open campaign README or session notes and log
+func (m Model) renderCampaignDocument() string {
+	if m.selectedCampaign == nil {
+		return "No campaign selected"
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("15"))
+
+	title := m.selectedCampaign.Name + " / README"
+	if m.campaignViewMode == CampaignViewSession && m.selectedSession != nil {
+		title = m.selectedCampaign.Name + " / " + m.selectedSession.Name
+	}
+
+	header := fmt.Sprintf("%s  %s", titleStyle.Render(title), renderScrollIndicator(m.campaignViewport))
+	gm := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Gamemaster: %s", m.selectedCampaign.Gamemaster))
+
+	return strings.Join([]string{header, gm, "", m.campaignViewport.View()}, "\n")
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// characterConflictHeaderLines is the number of lines around the differences in the conflict dialog
+const characterConflictHeaderLines = 12
+
+// characterConflict is the state of the dialog shown when an edited character could
+// not be saved because someone else saved the character after editing started
+type characterConflict struct {
+	active   bool
+	theirs   dfm.Character // Character as saved by someone else
+	showDiff bool          // Whether the differences to the edited character are shown
+}
+
+// setStaleCharacter handles a save that failed because the character has been changed
+// by someone else. The editor opens the conflict dialog. Play changes in the detail view
+// show the current character so the change can be made again.
+func (m *Model) setStaleCharacter(err error) {
+	var stale *dfdb.StaleRevisionError
+	if !errors.As(err, &stale) {
+		return
+	}
+
+	if m.characterViewMode == CharacterViewEdit {
+		m.characterConflict = characterConflict{active: true, theirs: stale.Current}
+		m.characterSaveErr = nil
+		return
+	}
+	m.setSavedCharacter(stale.Current)
+	m.characterSaveErr = fmt.Errorf("%s was changed by someone else, try again", stale.Current.Name)
+}
+
+// updateCharacterConflict handles the keys of the conflict dialog: reload the saved
+// character, overwrite it with the edited one or show the differences between them.
+func (m Model) updateCharacterConflict(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	conflict := &m.characterConflict
+	switch msg.String() {
+	case "ctrl+c":
+		return m, nil, false
+
+	case "r":
+		// Start over from their version, dropping the edits
+		m.characterEditor = newCharacterEditor(conflict.theirs)
+		*conflict = characterConflict{}
+		return m, nil, true
+
+	case "o":
+		// Save the edited character on top of their version
+		m.characterEditor.base.Revision = conflict.theirs.Revision
+		m.characterEditor.draft.Revision = conflict.theirs.Revision
+		*conflict = characterConflict{}
+		m.characterSaving = true
+		return m, saveCharacter(m.username, m.backend, m.characterEditor.draft), true
+
+	case "d":
+		conflict.showDiff = !conflict.showDiff
+		return m, nil, true
+
+	case "esc":
+		// Keep editing; saving will conflict again until reloaded or overwritten
+		*conflict = characterConflict{}
+		return m, nil, true
+	}
+	return m, nil, true
+}
+
+// renderCharacterConflict renders the conflict dialog, with the differences between
+// the saved and the edited character if requested
+func (m Model) renderCharacterConflict() string {
+	conflict := m.characterConflict
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	boxStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("11")).Padding(1, 2)
+
+	diffAction := "Show"
+	if conflict.showDiff {
+		diffAction = "Hide"
+	}
+
+	var lines []string
+	lines = append(lines, warningStyle.Render("Conflict: "+conflict.theirs.Name+" was changed by someone else while you were editing"))
+	lines = append(lines, "")
+	lines = append(lines, "r: Reload their version, dropping your changes")
+	lines = append(lines, "o: Overwrite their version with yours")
+	lines = append(lines, "d: "+diffAction+" the differences")
+	lines = append(lines, "Esc: Back to editing")
+
+	if conflict.showDiff {
+		lines = append(lines, "")
+		changes := dfm.Diff(conflict.theirs, m.characterEditor.draft)
+		if len(changes) == 0 {
+			lines = append(lines, hintStyle.Render("The versions are the same"))
+		}
+
+		_, height := m.documentSize(characterConflictHeaderLines)
+		lines = append(lines, renderFieldChanges(changes, height)...)
+		lines = append(lines, hintStyle.Render("- theirs, + yours"))
+	}
+
+	return fmt.Sprintf("%s\n\n%s",
+		titleStyle.Render("Edit "+m.characterEditor.base.Name),
+		boxStyle.Render(strings.Join(lines, "\n")))
+}
+
+// renderFieldChanges renders changed fields with their old (-) and new (+) values in at most height lines
+func renderFieldChanges(changes []dfm.FieldChange, height int) []string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	fieldStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Width(28)
+	oldStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	newStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+
+	height = max(height, 1)
+	var lines []string
+	for i, change := range changes {
+		if i == height-1 && len(changes) > height {
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("... and %d more", len(changes)-i)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			fieldStyle.Render(change.Field),
+			oldStyle.Render("- "+diffValue(change.Old)),
+			newStyle.Render("+ "+diffValue(change.New))))
+	}
+	return lines
+}
+
+// diffValue returns a changed value for display, marking values of missing list items
+func diffValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
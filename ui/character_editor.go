@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// characterEditorHeaderLines is the number of lines above and below the editor form
+const characterEditorHeaderLines = 4
+
+// characterSavedMsg is sent when an edited character has been saved or saving failed
+type characterSavedMsg struct {
+	character dfm.Character
+	err       error
+}
+
+// saveCharacter stores an edited character through the backend
+func saveCharacter(username string, backend services.Backend, character dfm.Character) tea.Cmd {
+	return func() tea.Msg {
+		saved, err := backend.UpdateCharacter(username, character)
+		return characterSavedMsg{
+			character: saved,
+			err:       err,
+		}
+	}
+}
+
+// newCharacterEditor creates a form with all editable fields of a character
+func newCharacterEditor(c dfm.Character) characterForm {
+	var fields []formField
+	fields = append(fields, basicFields(c)...)
+	fields = append(fields, fateFields(c)...)
+	fields = append(fields, aspectFields(c)...)
+	fields = append(fields, skillFields(c)...)
+	fields = append(fields, stuntFields(c)...)
+	fields = append(fields, disciplineFields(c)...)
+	fields = append(fields, stressFields(c)...)
+	fields = append(fields, consequenceFields(c)...)
+	return newCharacterForm(c, fields)
+}
+
+// updateCharacters handles key presses of the character editor, the creation wizard,
+// the detail view and the character history.
+// It returns false if the key was not handled so list navigation and global keys keep working.
+func (m Model) updateCharacters(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch m.characterViewMode {
+	case CharacterViewList:
+		// The search prompt captures typed keys while open
+		if m.characterSearch.active {
+			return m.updateCharacterSearch(msg)
+		}
+		if m, handled := m.updateCharacterFilters(msg); handled {
+			return m, nil, true
+		}
+		switch msg.String() {
+		case "n":
+			m.characterWizard = characterWizard{}
+			m.characterViewMode = CharacterViewCreate
+			return m, nil, true
+		case "d":
+			m, cmd := m.openCharacterHistory(true)
+			return m, cmd, true
+		case "o":
+			m.cycleCharacterSort()
+			return m, nil, true
+		}
+
+	case CharacterViewCreate:
+		return m.updateCharacterWizard(msg)
+
+	case CharacterViewDetail:
+		if msg.String() == "e" && m.selectedCharacter != nil && !m.consequencePrompt.active && !m.characterSaving {
+			m.characterEditor = newCharacterEditor(*m.selectedCharacter)
+			m.characterConflict = characterConflict{}
+			m.characterSaveErr = nil
+			m.characterViewMode = CharacterViewEdit
+			return m, nil, true
+		}
+		if msg.String() == "v" && m.selectedCharacter != nil && !m.consequencePrompt.active {
+			m, cmd := m.openCharacterHistory(false)
+			return m, cmd, true
+		}
+		if updated, cmd, handled := m.updateCharacterPlay(msg); handled {
+			return updated, cmd, true
+		}
+
+		// Scroll the character sheet
+		vp, handled := updateDocumentViewport(m.characterDetailViewport(), msg)
+		m.characterViewport = vp
+		return m, nil, handled
+
+	case CharacterViewHistory:
+		return m.updateCharacterHistory(msg)
+
+	case CharacterViewEdit:
+		if m.characterConflict.active {
+			return m.updateCharacterConflict(msg)
+		}
+
+		// The editor takes all keys so typing does not switch tabs or quit
+		switch msg.String() {
+		case "ctrl+c":
+			return m, nil, false
+
+		case "esc":
+			// Discard the changes
+			m.characterViewMode = CharacterViewDetail
+			m.characterSaveErr = nil
+			return m, nil, true
+
+		case "ctrl+s":
+			if m.characterSaving {
+				return m, nil, true
+			}
+			if count := m.characterEditor.errorCount(); count > 0 {
+				m.characterSaveErr = fmt.Errorf("fix the %d invalid field(s) before saving", count)
+				return m, nil, true
+			}
+			m.characterSaving = true
+			m.characterSaveErr = nil
+			return m, saveCharacter(m.username, m.backend, m.characterEditor.draft), true
+		}
+
+		m.characterEditor = m.characterEditor.update(msg)
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// setSavedCharacter replaces a saved character in the character list and
+// returns to the detail view if the character is being edited
+func (m *Model) setSavedCharacter(character dfm.Character) {
+	for i := range m.characters {
+		if m.characters[i].ID == character.ID {
+			m.characters[i] = character
+			if m.selectedCharacter != nil && m.selectedCharacter.ID == character.ID {
+				m.selectedCharacter = &m.characters[i]
+			}
+		}
+	}
+	if m.characterViewMode == CharacterViewEdit {
+		m.characterViewMode = CharacterViewDetail
+	}
+}
+
+// renderCharacterEditor renders the character editor form with its validation status
+func (m Model) renderCharacterEditor() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if m.characterConflict.active {
+		return m.renderCharacterConflict()
+	}
+
+	_, height := m.documentSize(characterEditorHeaderLines)
+
+	status := hintStyle.Render("All fields are valid")
+	switch {
+	case m.characterSaving:
+		status = hintStyle.Render("Saving...")
+	case m.characterSaveErr != nil:
+		status = errorStyle.Render(fmt.Sprintf("Save failed: %v", m.characterSaveErr))
+	case m.characterEditor.errorCount() > 0:
+		status = errorStyle.Render(fmt.Sprintf("%d invalid field(s)", m.characterEditor.errorCount()))
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s",
+		titleStyle.Render("Edit "+m.characterEditor.base.Name),
+		m.characterEditor.view(height),
+		status)
+}
@@ -0,0 +1,392 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// formField is one editable value of a character form
+type formField struct {
+	section string   // Heading shown above the first field of a section
+	label   string   // Field label
+	keys    []string // Character fields whose dfm.FieldError is shown on this field
+	input   textinput.Model
+	apply   func(c *dfm.Character, value string) error // Parses the value into the character
+	err     error                                      // Parse or validation error of the current value
+}
+
+// newFormField creates a form field with a single-line text input
+func newFormField(section, label, value string, apply func(*dfm.Character, string) error, keys ...string) formField {
+	input := textinput.New()
+	input.Prompt = ""
+	input.SetValue(value)
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return formField{
+		section: section,
+		label:   label,
+		keys:    keys,
+		input:   input,
+		apply:   apply,
+	}
+}
+
+// characterForm edits a character through a list of fields that are validated as you type
+type characterForm struct {
+	base   dfm.Character // Character the field values are applied to
+	draft  dfm.Character // Base character with all field values applied
+	fields []formField
+	focus  int // Index of the focused field
+}
+
+// newCharacterForm creates a form for the given fields and validates their initial values
+func newCharacterForm(base dfm.Character, fields []formField) characterForm {
+	f := characterForm{base: base, fields: fields}
+	f.setFocus(0)
+	f.validate()
+	return f
+}
+
+// setFocus moves the cursor to a field
+func (f *characterForm) setFocus(index int) {
+	if len(f.fields) == 0 {
+		return
+	}
+	index = max(0, min(index, len(f.fields)-1))
+	f.fields[f.focus].input.Blur()
+	f.focus = index
+	f.fields[f.focus].input.Focus()
+	f.fields[f.focus].input.CursorEnd()
+}
+
+// validate applies every field to a copy of the base character and records
+// the parse errors and the rule violations reported by dfm.Character.Validate
+func (f *characterForm) validate() {
+	draft := f.base.Clone()
+	for i := range f.fields {
+		f.fields[i].err = f.fields[i].apply(&draft, strings.TrimSpace(f.fields[i].input.Value()))
+	}
+
+	var validationErr *dfm.ValidationError
+	if errors.As(draft.Validate(), &validationErr) {
+		for _, fieldErr := range validationErr.Fields {
+			for i := range f.fields {
+				if f.fields[i].err == nil && slices.Contains(f.fields[i].keys, fieldErr.Field) {
+					f.fields[i].err = errors.New(fieldErr.Message)
+					break
+				}
+			}
+		}
+	}
+	f.draft = draft
+}
+
+// errorCount returns the number of fields with an invalid value
+func (f characterForm) errorCount() int {
+	count := 0
+	for _, field := range f.fields {
+		if field.err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// update moves between fields and passes other keys to the focused input.
+// Changed values are validated immediately.
+func (f characterForm) update(msg tea.KeyMsg) characterForm {
+	switch msg.String() {
+	case "tab", "down", "enter":
+		f.setFocus(f.focus + 1)
+		return f
+	case "shift+tab", "up":
+		f.setFocus(f.focus - 1)
+		return f
+	case "pgdown":
+		f.setFocus(f.focus + 10)
+		return f
+	case "pgup":
+		f.setFocus(f.focus - 10)
+		return f
+	}
+
+	if len(f.fields) == 0 {
+		return f
+	}
+	before := f.fields[f.focus].input.Value()
+	f.fields[f.focus].input, _ = f.fields[f.focus].input.Update(msg)
+	if f.fields[f.focus].input.Value() != before {
+		f.validate()
+	}
+	return f
+}
+
+// view renders the fields that fit in height lines, keeping the focused field visible
+func (f characterForm) view(height int) string {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Width(22)
+	focusedLabelStyle := labelStyle.Foreground(lipgloss.Color("15")).Background(lipgloss.Color("237"))
+	sectionStyle := lipgloss.NewStyle().Bold(true)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var lines []string
+	focusLine := 0
+	section := ""
+	for i, field := range f.fields {
+		if field.section != section {
+			section = field.section
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, sectionStyle.Render(section+":"))
+		}
+
+		label := labelStyle.Render(field.label)
+		if i == f.focus {
+			label = focusedLabelStyle.Render(field.label)
+			focusLine = len(lines)
+		}
+		line := "  " + label + " " + field.input.View()
+		if field.err != nil {
+			line += "  " + errorStyle.Render("✗ "+field.err.Error())
+		}
+		lines = append(lines, line)
+	}
+
+	// Scroll the field list so the focused field stays in view
+	height = max(height-1, 1)
+	if len(lines) <= height {
+		return strings.Join(lines, "\n")
+	}
+	start := max(0, min(focusLine-height/2, len(lines)-height))
+	view := lines[start : start+height]
+	return strings.Join(view, "\n") + "\n" +
+		hintStyle.Render(fmt.Sprintf("Field %d of %d", f.focus+1, len(f.fields)))
+}
+
+// parseNumber parses a whole number typed into a form field
+func parseNumber(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("must be a whole number")
+	}
+	return number, nil
+}
+
+// parseList parses a comma-separated list typed into a form field, dropping empty items
+func parseList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseStress parses a stress track typed as current/limit, e.g. "1/3"
+func parseStress(value string) (int, int, error) {
+	currentText, limitText, ok := strings.Cut(value, "/")
+	current, currentErr := strconv.Atoi(strings.TrimSpace(currentText))
+	limit, limitErr := strconv.Atoi(strings.TrimSpace(limitText))
+	if !ok || currentErr != nil || limitErr != nil {
+		return 0, 0, errors.New("use current/limit, e.g. 1/3")
+	}
+	return current, limit, nil
+}
+
+// basicFields returns the form fields for the name, aliases and tags of a character
+func basicFields(c dfm.Character) []formField {
+	return []formField{
+		nameField("Basic Information", c),
+		newFormField("Basic Information", "Aliases", strings.Join(c.Aliases, ", "), func(c *dfm.Character, value string) error {
+			c.Aliases = parseList(value)
+			return nil
+		}),
+		newFormField("Basic Information", "Tags", strings.Join(c.Tags, ", "), func(c *dfm.Character, value string) error {
+			c.Tags = parseList(value)
+			return nil
+		}),
+	}
+}
+
+// nameField returns a form field for the name of a character.
+// Stored characters may be nameless, but the forms require a name.
+func nameField(section string, c dfm.Character) formField {
+	return newFormField(section, "Name", c.Name, func(c *dfm.Character, value string) error {
+		c.Name = value
+		if value == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}, "name")
+}
+
+// fateFields returns the form fields for the refresh and fate points of a character
+func fateFields(c dfm.Character) []formField {
+	return []formField{
+		newFormField("Fate", "Refresh", strconv.Itoa(c.Refresh), func(c *dfm.Character, value string) error {
+			number, err := parseNumber(value)
+			c.Refresh = number
+			return err
+		}, "refresh"),
+		newFormField("Fate", "Fate points", strconv.Itoa(c.FatePoint), func(c *dfm.Character, value string) error {
+			number, err := parseNumber(value)
+			c.FatePoint = number
+			return err
+		}, "fatePoint"),
+	}
+}
+
+// aspectFields returns a form field for the title of each aspect of a character
+func aspectFields(c dfm.Character) []formField {
+	var fields []formField
+	for i, aspect := range c.Aspects {
+		fields = append(fields, newFormField("Aspects", aspectTypeName(aspect.Type), aspect.Title, func(c *dfm.Character, value string) error {
+			c.Aspects[i].Title = value
+			return nil
+		}, fmt.Sprintf("aspects[%d]", i)))
+	}
+	return fields
+}
+
+// skillFields returns a form field for the rating of each skill of a character
+func skillFields(c dfm.Character) []formField {
+	var fields []formField
+	for i, skill := range c.Skills {
+		fields = append(fields, newFormField("Skills", strings.Title(skill.Title), strconv.Itoa(skill.Rating), func(c *dfm.Character, value string) error {
+			number, err := parseNumber(value)
+			c.Skills[i].Rating = number
+			return err
+		}, fmt.Sprintf("skills[%d]", i)))
+	}
+	return fields
+}
+
+// stuntFields returns form fields for the title and description of each stunt of a
+// character, followed by empty fields for adding a new stunt
+func stuntFields(c dfm.Character) []formField {
+	var fields []formField
+	for i := 0; i <= len(c.Stunts); i++ {
+		label := fmt.Sprintf("Stunt %d", i+1)
+		var stunt dfm.Stunt
+		if i < len(c.Stunts) {
+			stunt = c.Stunts[i]
+		} else {
+			label = "New stunt"
+		}
+
+		// The new stunt is only added once something is typed into it
+		stuntAt := func(c *dfm.Character, value string) *dfm.Stunt {
+			if i >= len(c.Stunts) {
+				if value == "" {
+					return nil
+				}
+				c.Stunts = append(c.Stunts, dfm.Stunt{})
+			}
+			return &c.Stunts[i]
+		}
+		fields = append(fields,
+			newFormField("Stunts", label, stunt.Title, func(c *dfm.Character, value string) error {
+				if stunt := stuntAt(c, value); stunt != nil {
+					stunt.Title = value
+				}
+				return nil
+			}, fmt.Sprintf("stunts[%d]", i)),
+			newFormField("Stunts", "  description", stunt.Description, func(c *dfm.Character, value string) error {
+				if stunt := stuntAt(c, value); stunt != nil {
+					stunt.Description = value
+				}
+				return nil
+			}),
+		)
+	}
+	return fields
+}
+
+// disciplineFields returns form fields for the blood potency and the rating of each
+// discipline of a vampire character. Other characters have no disciplines.
+func disciplineFields(c dfm.Character) []formField {
+	if c.Spirit != string(dfm.SpiritVampire) {
+		return nil
+	}
+
+	fields := []formField{
+		newFormField("Beast and Blood", "Blood Potency", strconv.Itoa(c.BloodPotency), func(c *dfm.Character, value string) error {
+			number, err := parseNumber(value)
+			c.BloodPotency = number
+			return err
+		}, "bloodPotency"),
+	}
+	for i, discipline := range c.Disciplines {
+		fields = append(fields, newFormField("Beast and Blood", strings.Title(discipline.Title), strconv.Itoa(discipline.Rating), func(c *dfm.Character, value string) error {
+			number, err := parseNumber(value)
+			c.Disciplines[i].Rating = number
+			return err
+		}, fmt.Sprintf("disciplines[%d]", i)))
+	}
+	return fields
+}
+
+// stressFields returns form fields for the stress tracks of a character, typed as current/limit
+func stressFields(c dfm.Character) []formField {
+	stressField := func(label, track string, current, limit int, set func(c *dfm.Character, current, limit int)) formField {
+		return newFormField("Stress Tracks", label, fmt.Sprintf("%d/%d", current, limit), func(c *dfm.Character, value string) error {
+			current, limit, err := parseStress(value)
+			if err == nil {
+				set(c, current, limit)
+			}
+			return err
+		}, track+"StressCurrent", track+"StressLimit")
+	}
+
+	fields := []formField{
+		stressField("Physical", "physical", c.PhysicalStressCurrent, c.PhysicalStressLimit, func(c *dfm.Character, current, limit int) {
+			c.PhysicalStressCurrent, c.PhysicalStressLimit = current, limit
+		}),
+		stressField("Mental", "mental", c.MentalStressCurrent, c.MentalStressLimit, func(c *dfm.Character, current, limit int) {
+			c.MentalStressCurrent, c.MentalStressLimit = current, limit
+		}),
+	}
+	if c.Spirit == string(dfm.SpiritVampire) {
+		fields = append(fields, stressField("Hunger", "hunger", c.HungerStressCurrent, c.HungerStressLimit, func(c *dfm.Character, current, limit int) {
+			c.HungerStressCurrent, c.HungerStressLimit = current, limit
+		}))
+	}
+	return fields
+}
+
+// consequenceFields returns a form field for each consequence of a character.
+// A consequence is active while it has a title.
+func consequenceFields(c dfm.Character) []formField {
+	var fields []formField
+	for i, consequence := range c.Consequences {
+		fields = append(fields, newFormField("Consequences", consequenceName(consequence.Level), consequence.Title, func(c *dfm.Character, value string) error {
+			c.Consequences[i].Title = value
+			c.Consequences[i].IsActive = value != ""
+			return nil
+		}, fmt.Sprintf("consequences[%d]", i)))
+	}
+	return fields
+}
+
+// consequenceName returns the display name of a consequence level, e.g. "Mild (2)"
+func consequenceName(level int) string {
+	switch level {
+	case 2:
+		return "Mild (2)"
+	case 4:
+		return "Moderate (4)"
+	case 6:
+		return "Severe (6)"
+	}
+	return fmt.Sprintf("Level %d", level)
+}
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// characterHistoryHeaderLines is the number of lines above and below the history entries
+const characterHistoryHeaderLines = 6
+
+// historyTimeFormat is the format of the times of history entries
+const historyTimeFormat = "2006-01-02 15:04"
+
+// characterHistory is the state of browsing the history of a character or the deleted characters
+type characterHistory struct {
+	deleted   bool                        // Browsing deleted characters instead of the history of the selected character
+	entries   []dfm.CharacterHistoryEntry // History entries, newest first
+	index     int                         // Index of the selected entry
+	loading   bool
+	confirm   bool // Waiting for the restore of the selected entry to be confirmed
+	restoring bool
+	restored  string // Message about the last restore
+	err       error  // Error from loading or restoring
+}
+
+// characterHistoryLoadedMsg is sent when the history of a character or the deleted characters are loaded
+type characterHistoryLoadedMsg struct {
+	entries []dfm.CharacterHistoryEntry
+	err     error
+}
+
+// characterRestoredMsg is sent when a version of a character has been restored or restoring failed
+type characterRestoredMsg struct {
+	character dfm.Character
+	sequence  int
+	err       error
+}
+
+// loadCharacterHistory loads the history of a character, newest entry first
+func loadCharacterHistory(username string, backend services.Backend, characterID string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := backend.GetCharacterHistory(username, characterID)
+		slices.Reverse(entries)
+		return characterHistoryLoadedMsg{entries: entries, err: err}
+	}
+}
+
+// loadDeletedCharacters loads the deleted characters the user may restore
+func loadDeletedCharacters(username string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := backend.GetDeletedCharacters(username)
+		return characterHistoryLoadedMsg{entries: entries, err: err}
+	}
+}
+
+// restoreCharacter restores the version of a character in a history entry
+func restoreCharacter(username string, backend services.Backend, entry dfm.CharacterHistoryEntry) tea.Cmd {
+	return func() tea.Msg {
+		character, err := backend.RestoreCharacter(username, entry.CharacterID, entry.Sequence)
+		return characterRestoredMsg{character: character, sequence: entry.Sequence, err: err}
+	}
+}
+
+// openCharacterHistory shows the history of the selected character, or the deleted characters
+func (m Model) openCharacterHistory(deleted bool) (Model, tea.Cmd) {
+	m.characterHistory = characterHistory{deleted: deleted, loading: true}
+	m.characterViewMode = CharacterViewHistory
+	if deleted {
+		return m, loadDeletedCharacters(m.username, m.backend)
+	}
+	return m, loadCharacterHistory(m.username, m.backend, m.selectedCharacter.ID)
+}
+
+// reloadCharacterHistory reloads the entries shown in the history view
+func (m Model) reloadCharacterHistory() tea.Cmd {
+	if m.characterHistory.deleted {
+		return loadDeletedCharacters(m.username, m.backend)
+	}
+	if m.selectedCharacter == nil {
+		return nil
+	}
+	return loadCharacterHistory(m.username, m.backend, m.selectedCharacter.ID)
+}
+
+// updateCharacterHistory handles key presses while browsing a history.
+// A selected version is restored with r, after confirming with y.
+func (m Model) updateCharacterHistory(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	h := &m.characterHistory
+	if h.confirm {
+		h.confirm = false
+		if msg.String() == "y" && h.index < len(h.entries) {
+			h.restoring = true
+			h.err = nil
+			return m, restoreCharacter(m.username, m.backend, h.entries[h.index]), true
+		}
+		return m, nil, msg.String() != "ctrl+c"
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.characterViewMode = CharacterViewDetail
+		if h.deleted || m.selectedCharacter == nil {
+			m.characterViewMode = CharacterViewList
+		}
+		return m, nil, true
+
+	case "up", "k":
+		if h.index > 0 {
+			h.index--
+		}
+		return m, nil, true
+
+	case "down", "j":
+		if h.index < len(h.entries)-1 {
+			h.index++
+		}
+		return m, nil, true
+
+	case "r":
+		if !h.loading && !h.restoring && h.index < len(h.entries) {
+			h.confirm = true
+			h.restored = ""
+		}
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// setCharacterHistory shows loaded history entries, keeping the selection in range
+func (m *Model) setCharacterHistory(msg characterHistoryLoadedMsg) {
+	h := &m.characterHistory
+	h.loading = false
+	h.entries = msg.entries
+	h.err = msg.err
+	h.index = max(0, min(h.index, len(h.entries)-1))
+}
+
+// setRestoredCharacter handles the result of a restore and reloads the history.
+// The restored character reaches the character list through the change events.
+func (m *Model) setRestoredCharacter(msg characterRestoredMsg) tea.Cmd {
+	h := &m.characterHistory
+	h.restoring = false
+	if msg.err != nil {
+		h.err = fmt.Errorf("could not restore: %w", msg.err)
+		return nil
+	}
+	h.restored = fmt.Sprintf("Restored %s to version #%d", msg.character.Name, msg.sequence)
+	if m.characterViewMode != CharacterViewHistory {
+		return nil
+	}
+	h.loading = true
+	h.index = 0
+	return m.reloadCharacterHistory()
+}
+
+// renderCharacterHistory renders the history entries and the changed fields of the selected entry
+func (m Model) renderCharacterHistory() string {
+	h := m.characterHistory
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+
+	title := "Deleted Characters"
+	if !h.deleted && m.selectedCharacter != nil {
+		title = "History of " + m.selectedCharacter.Name
+	}
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, "")
+
+	switch {
+	case h.loading:
+		lines = append(lines, hintStyle.Render("Loading..."))
+	case len(h.entries) == 0 && h.deleted:
+		lines = append(lines, hintStyle.Render("No deleted characters to restore"))
+	case len(h.entries) == 0:
+		lines = append(lines, hintStyle.Render("No recorded changes"))
+	default:
+		// Entries take up to half of the height, the changed fields the rest
+		_, height := m.documentSize(characterHistoryHeaderLines)
+		listHeight := max(1, height/2)
+		start := max(0, min(h.index-listHeight/2, len(h.entries)-listHeight))
+		end := min(len(h.entries), start+listHeight)
+		for i := start; i < end; i++ {
+			lines = append(lines, renderHistoryEntry(h.entries[i], h.deleted, h.index == i))
+		}
+		if end-start < len(h.entries) {
+			lines = append(lines, hintStyle.Render(fmt.Sprintf("Entry %d of %d", h.index+1, len(h.entries))))
+		}
+
+		entry := h.entries[h.index]
+		lines = append(lines, "")
+		if len(entry.Changes) == 0 {
+			lines = append(lines, hintStyle.Render("No changed fields recorded for this entry"))
+		} else {
+			lines = append(lines, labelStyle.Render("Changed fields:"))
+			lines = append(lines, renderFieldChanges(entry.Changes, height-listHeight-3)...)
+		}
+	}
+
+	lines = append(lines, "")
+	switch {
+	case h.confirm:
+		entry := h.entries[h.index]
+		lines = append(lines, warningStyle.Render(fmt.Sprintf("Restore %s to version #%d? ", entry.Character.Name, entry.Sequence))+
+			hintStyle.Render("y: restore, any other key: cancel"))
+	case h.restoring:
+		lines = append(lines, hintStyle.Render("Restoring..."))
+	case h.err != nil:
+		lines = append(lines, errorStyle.Render(h.err.Error()))
+	case h.restored != "":
+		lines = append(lines, hintStyle.Render(h.restored))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderHistoryEntry renders a history entry as a list item: when, what and by whom
+func renderHistoryEntry(entry dfm.CharacterHistoryEntry, deleted, selected bool) string {
+	username := entry.Username
+	if username == "" {
+		username = "outside dftui"
+	}
+	when := entry.Time.Local().Format(historyTimeFormat)
+
+	if deleted {
+		return renderListItem(entry.Character.Name, fmt.Sprintf("deleted %s by %s", when, username), selected)
+	}
+
+	description := fmt.Sprintf("%s by %s", entry.Type, username)
+	switch {
+	case entry.Type == dfm.HistoryRestored:
+		description = fmt.Sprintf("restore of #%d by %s", entry.RestoredSequence, username)
+	case len(entry.Changes) == 1:
+		description += ", 1 field"
+	case len(entry.Changes) > 1:
+		description += fmt.Sprintf(", %d fields", len(entry.Changes))
+	}
+	return renderListItem(fmt.Sprintf("#%d %s", entry.Sequence, when), description, selected)
+}
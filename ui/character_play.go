@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// consequencePrompt is the state of taking or recovering a consequence in the character detail view
+type consequencePrompt struct {
+	active  bool
+	recover bool   // Recovering from a consequence instead of taking one
+	level   int    // Chosen consequence level, 0 until chosen
+	title   string // Title typed for the consequence being taken
+}
+
+// stressKeys maps the quick-action keys that mark and clear stress to their stress track
+var stressKeys = map[string]struct {
+	track dfm.StressTrack
+	mark  bool
+}{
+	"p": {dfm.StressPhysical, true},
+	"P": {dfm.StressPhysical, false},
+	"m": {dfm.StressMental, true},
+	"M": {dfm.StressMental, false},
+	"h": {dfm.StressHunger, true},
+	"H": {dfm.StressHunger, false},
+}
+
+// changeCharacter applies a play change to a copy of the selected character and saves it.
+// The detail view shows the change once it has been stored. Changes that break
+// the rules are not saved, and keys are ignored while a change is being saved.
+func (m Model) changeCharacter(change func(c *dfm.Character) error) (Model, tea.Cmd) {
+	if m.selectedCharacter == nil || m.characterSaving {
+		return m, nil
+	}
+
+	updated := m.selectedCharacter.Clone()
+	if err := change(&updated); err != nil {
+		m.characterSaveErr = err
+		return m, nil
+	}
+	m.characterSaving = true
+	m.characterSaveErr = nil
+	return m, saveCharacter(m.username, m.backend, updated)
+}
+
+// updateCharacterPlay handles the quick-action keys of the character detail view
+// for tracking fate points, stress and consequences during play.
+// It returns false if the key was not handled.
+func (m Model) updateCharacterPlay(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.consequencePrompt.active {
+		return m.updateConsequencePrompt(msg)
+	}
+
+	key := msg.String()
+	if stress, ok := stressKeys[key]; ok {
+		m, cmd := m.changeCharacter(func(c *dfm.Character) error {
+			if stress.mark {
+				return c.MarkStress(stress.track)
+			}
+			return c.ClearStress(stress.track)
+		})
+		return m, cmd, true
+	}
+
+	switch key {
+	case "+":
+		m, cmd := m.changeCharacter(func(c *dfm.Character) error {
+			c.GainFatePoint()
+			return nil
+		})
+		return m, cmd, true
+
+	case "-":
+		m, cmd := m.changeCharacter(func(c *dfm.Character) error {
+			return c.SpendFatePoint()
+		})
+		return m, cmd, true
+
+	case "c", "r":
+		m.consequencePrompt = consequencePrompt{active: true, recover: key == "r"}
+		m.characterSaveErr = nil
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// updateConsequencePrompt handles typing in the consequence prompt: first the level, then
+// the title of a consequence being taken. A recovered consequence needs only the level.
+func (m Model) updateConsequencePrompt(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	prompt := &m.consequencePrompt
+	if msg.String() == "ctrl+c" {
+		return m, nil, false
+	}
+	if msg.Type == tea.KeyEsc {
+		*prompt = consequencePrompt{}
+		return m, nil, true
+	}
+
+	// Choose the level
+	if prompt.level == 0 {
+		level := 0
+		switch msg.String() {
+		case "2":
+			level = 2
+		case "4":
+			level = 4
+		case "6":
+			level = 6
+		default:
+			return m, nil, true
+		}
+
+		if prompt.recover {
+			*prompt = consequencePrompt{}
+			m, cmd := m.changeCharacter(func(c *dfm.Character) error {
+				return c.RecoverConsequence(level)
+			})
+			return m, cmd, true
+		}
+		// Refuse a full level before the title is typed
+		if err := m.selectedCharacter.CanTakeConsequence(level); err != nil {
+			*prompt = consequencePrompt{}
+			m.characterSaveErr = err
+			return m, nil, true
+		}
+		prompt.level = level
+		return m, nil, true
+	}
+
+	// Type the title
+	switch msg.Type {
+	case tea.KeyEnter:
+		level, title := prompt.level, prompt.title
+		if title == "" {
+			return m, nil, true
+		}
+		*prompt = consequencePrompt{}
+		m, cmd := m.changeCharacter(func(c *dfm.Character) error {
+			return c.TakeConsequence(level, title)
+		})
+		return m, cmd, true
+	case tea.KeyBackspace:
+		if runes := []rune(prompt.title); len(runes) > 0 {
+			prompt.title = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		prompt.title += string(msg.Runes)
+	}
+	return m, nil, true
+}
+
+// renderCharacterPlayStatus renders the consequence prompt or the result of the last play change
+func (m Model) renderCharacterPlayStatus() string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+
+	prompt := m.consequencePrompt
+	switch {
+	case prompt.active && prompt.recover:
+		return valueStyle.Render("Recover from which consequence? ") + hintStyle.Render("2: mild, 4: moderate, 6: severe, Esc: cancel")
+	case prompt.active && prompt.level == 0:
+		return valueStyle.Render("Take which consequence? ") + hintStyle.Render("2: mild, 4: moderate, 6: severe, Esc: cancel")
+	case prompt.active:
+		return fmt.Sprintf("%s %s█ %s",
+			valueStyle.Render(consequenceName(prompt.level)+":"),
+			valueStyle.Render(prompt.title),
+			hintStyle.Render("(Enter: take, Esc: cancel)"))
+	case m.characterSaving:
+		return hintStyle.Render("Saving...")
+	case m.characterSaveErr != nil:
+		return errorStyle.Render(m.characterSaveErr.Error())
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// characterSearch is the search text of the character list. The spirit, group and
+// collective filters are kept in the character query.
+type characterSearch struct {
+	active bool   // Whether the search prompt is open and takes the typed keys
+	text   string // Text matched fuzzily against names, aliases and tags
+}
+
+// characterSpiritFilters and characterGroupFilters are the values "s" and "g" cycle through,
+// empty for all characters
+var (
+	characterSpiritFilters = []string{"", string(dfm.SpiritVampire), string(dfm.SpiritGhoul), string(dfm.SpiritHuman)}
+	characterGroupFilters  = []string{"", string(dfm.PC), string(dfm.NPC)}
+)
+
+// updateCharacterSearch handles keys while the search prompt is open.
+// ↑/↓ are left to the list navigation, so the selection moves over the matching characters.
+func (m Model) updateCharacterSearch(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyDown, tea.KeyCtrlC:
+		return m, nil, false
+	case tea.KeyEnter:
+		// Keep the matching characters listed
+		m.characterSearch.active = false
+	case tea.KeyEsc:
+		m.keepCharacterSelection(func() {
+			m.characterSearch = characterSearch{}
+		})
+	case tea.KeyBackspace:
+		m.keepCharacterSelection(func() {
+			if runes := []rune(m.characterSearch.text); len(runes) > 0 {
+				m.characterSearch.text = string(runes[:len(runes)-1])
+			}
+		})
+	case tea.KeyRunes, tea.KeySpace:
+		m.keepCharacterSelection(func() {
+			m.characterSearch.text += string(msg.Runes)
+		})
+	}
+	return m, nil, true
+}
+
+// updateCharacterFilters handles the search and filter keys of the character list.
+func (m Model) updateCharacterFilters(msg tea.KeyMsg) (Model, bool) {
+	switch msg.String() {
+	case "/":
+		m.characterSearch.active = true
+	case "s":
+		m.keepCharacterSelection(func() {
+			m.characterQuery.Spirit = nextFilter(characterSpiritFilters, m.characterQuery.Spirit)
+		})
+	case "g":
+		m.keepCharacterSelection(func() {
+			m.characterQuery.Group = nextFilter(characterGroupFilters, m.characterQuery.Group)
+		})
+	case "c":
+		m.keepCharacterSelection(func() {
+			current := ""
+			if len(m.characterQuery.Collectives) > 0 {
+				current = m.characterQuery.Collectives[0]
+			}
+			m.characterQuery.Collectives = nil
+			if next := nextFilter(m.characterCollectives(), current); next != "" {
+				m.characterQuery.Collectives = []string{next}
+			}
+		})
+	case "esc":
+		if !m.characterFiltered() {
+			return m, false
+		}
+		m.keepCharacterSelection(func() {
+			m.characterSearch = characterSearch{}
+			m.characterQuery.Spirit = ""
+			m.characterQuery.Group = ""
+			m.characterQuery.Collectives = nil
+		})
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// keepCharacterSelection changes the search, filters or sort order of the character list.
+// The selected character stays selected if it is still listed, otherwise the first one is.
+func (m *Model) keepCharacterSelection(change func()) {
+	selectedID := ""
+	if i := m.selectedCharacterInList(); i >= 0 {
+		selectedID = m.characters[i].ID
+	}
+	change()
+	m.selectedCharacterIndex = indexOfCharacter(m.listedCharacters(), selectedID, 0)
+}
+
+// characterFiltered reports whether the character list is searched or filtered
+func (m Model) characterFiltered() bool {
+	q := m.characterQuery
+	return m.characterSearch.active || m.characterSearch.text != "" ||
+		q.Spirit != "" || q.Group != "" || len(q.Collectives) > 0
+}
+
+// characterCollectives returns the collectives of the characters to filter by, sorted, after an empty value for all
+func (m Model) characterCollectives() []string {
+	var collectives []string
+	for _, character := range m.characters {
+		for _, collective := range character.Collectives {
+			// The empty value is already the filter for all characters
+			if collective != "" {
+				collectives = append(collectives, collective)
+			}
+		}
+	}
+	slices.Sort(collectives)
+	return append([]string{""}, slices.Compact(collectives)...)
+}
+
+// nextFilter returns the value after current, wrapping around to the first one
+func nextFilter(values []string, current string) string {
+	i := slices.Index(values, current)
+	return values[(i+1)%len(values)]
+}
+
+// matchesCharacterSearch reports whether the search text fuzzily matches the name,
+// an alias or a tag of a character
+func matchesCharacterSearch(c dfm.Character, text string) bool {
+	for _, value := range slices.Concat([]string{c.Name}, c.Aliases, c.Tags) {
+		if _, ok := dfm.FuzzyMatch(text, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderCharacterFilters renders the status line of the search and active filters,
+// or an empty string if the list is not searched or filtered.
+func (m Model) renderCharacterFilters() string {
+	if !m.characterFiltered() {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var parts []string
+	if m.characterSearch.active {
+		parts = append(parts, fmt.Sprintf("%s %s█", labelStyle.Render("Search:"), valueStyle.Render(m.characterSearch.text)))
+	} else if m.characterSearch.text != "" {
+		parts = append(parts, fmt.Sprintf("%s %s", labelStyle.Render("Search:"), valueStyle.Render(m.characterSearch.text)))
+	}
+	if spirit := m.characterQuery.Spirit; spirit != "" {
+		parts = append(parts, fmt.Sprintf("%s %s", labelStyle.Render("Spirit:"), valueStyle.Render(spirit)))
+	}
+	if group := m.characterQuery.Group; group != "" {
+		parts = append(parts, fmt.Sprintf("%s %s", labelStyle.Render("Group:"), valueStyle.Render(strings.ToUpper(group))))
+	}
+	if len(m.characterQuery.Collectives) > 0 {
+		parts = append(parts, fmt.Sprintf("%s %s", labelStyle.Render("Collective:"), valueStyle.Render(m.characterQuery.Collectives[0])))
+	}
+
+	hint := "(Esc: clear)"
+	if m.characterSearch.active {
+		hint = "(Enter: done, Esc: clear search)"
+	}
+	return strings.Join(parts, hintStyle.Render(" | ")) + " " + hintStyle.Render(hint)
+}
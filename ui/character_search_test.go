@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+func TestCharacterCollectives(t *testing.T) {
+	m := Model{characters: []dfm.Character{
+		{Name: "Lucius", Collectives: []string{"Camarilla", ""}},
+		{Name: "Maria", Collectives: []string{""}},
+		{Name: "John", Collectives: []string{"Anarchs", "Camarilla"}},
+		{Name: "Nobody"},
+	}}
+
+	want := `["" "Anarchs" "Camarilla"]`
+	if got := fmt.Sprintf("%q", m.characterCollectives()); got != want {
+		t.Errorf("characterCollectives() = %s, want %s", got, want)
+	}
+
+	// "c" cycles through every collective and back to all characters
+	var filters []string
+	for range 3 {
+		m, _ = m.updateCharacterFilters(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+		filters = append(filters, fmt.Sprint(m.characterQuery.Collectives))
+	}
+	if got := fmt.Sprint(filters); got != "[[Anarchs] [Camarilla] []]" {
+		t.Errorf("Collective filters cycled through %s", got)
+	}
+}
+
+func TestRenderCharactersListsEveryGroup(t *testing.T) {
+	m := Model{username: "gm", characters: []dfm.Character{
+		{ID: "id-1", Name: "Mystery", Group: "unknown"},
+		{ID: "id-2", Name: "Nathan", Group: string(dfm.NPC)},
+		{ID: "id-3", Name: "Lucius", Group: string(dfm.PC)},
+	}}
+
+	// Every selectable character is rendered, so the selection never moves onto a hidden row
+	view := m.renderCharactersTab()
+	for _, character := range m.listedCharacters() {
+		if !strings.Contains(view, character.Name) {
+			t.Errorf("%s can be selected but is not shown:\n%s", character.Name, view)
+		}
+	}
+	if listed := m.listedCharacters(); listed[len(listed)-1].Name != "Mystery" {
+		t.Errorf("Characters without a valid group should be listed last, got %s", listed[len(listed)-1].Name)
+	}
+}
@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// characterWizardHeaderLines is the number of lines above and below the wizard form
+const characterWizardHeaderLines = 6
+
+// wizardStep is a step of the character creation wizard
+type wizardStep int
+
+const (
+	wizardStepSpirit      wizardStep = iota // Spirit and PC or NPC
+	wizardStepConcept                       // Name and aspects
+	wizardStepSkills                        // Skill ratings
+	wizardStepStunts                        // Stunts
+	wizardStepDisciplines                   // Blood potency and disciplines, vampires only
+)
+
+// wizardStepNames are the display names of the wizard steps
+var wizardStepNames = map[wizardStep]string{
+	wizardStepSpirit:      "Spirit",
+	wizardStepConcept:     "Concept",
+	wizardStepSkills:      "Skills",
+	wizardStepStunts:      "Stunts",
+	wizardStepDisciplines: "Disciplines",
+}
+
+// wizardSpirits are the spirits to choose from, with a short description each
+var wizardSpirits = []struct {
+	spirit      dfm.SpiritType
+	description string
+}{
+	{dfm.SpiritVampire, "Blood potency, disciplines and a hunger stress track"},
+	{dfm.SpiritGhoul, "A human bound to a vampire master"},
+	{dfm.SpiritHuman, "A plain Fate Condensed character"},
+}
+
+// characterWizard is the state of the character creation wizard
+type characterWizard struct {
+	step        wizardStep
+	spiritIndex int           // Index of the selected spirit in wizardSpirits
+	npc         bool          // Whether a non-player character is created
+	character   dfm.Character // Character built by the completed steps
+	form        characterForm // Form of the current step, unused in the spirit step
+	creating    bool          // Whether the character is being created
+	err         error         // Error from the last step change or from creating the character
+}
+
+// characterCreatedMsg is sent when a new character has been created or creating failed
+type characterCreatedMsg struct {
+	character dfm.Character
+	err       error
+}
+
+// createCharacter stores a new character through the backend
+func createCharacter(username string, backend services.Backend, character dfm.Character) tea.Cmd {
+	return func() tea.Msg {
+		return characterCreatedMsg{
+			character: character,
+			err:       backend.CreateCharacter(username, character),
+		}
+	}
+}
+
+// steps returns the wizard steps for the selected spirit.
+// Only vampires have the disciplines step.
+func (w characterWizard) steps() []wizardStep {
+	steps := []wizardStep{wizardStepSpirit, wizardStepConcept, wizardStepSkills, wizardStepStunts}
+	if wizardSpirits[w.spiritIndex].spirit == dfm.SpiritVampire {
+		steps = append(steps, wizardStepDisciplines)
+	}
+	return steps
+}
+
+// stepForm creates the form of a wizard step for the character built so far
+func stepForm(step wizardStep, c dfm.Character) characterForm {
+	var fields []formField
+	switch step {
+	case wizardStepConcept:
+		fields = append(fields, nameField("Concept", c))
+		fields = append(fields, aspectFields(c)...)
+	case wizardStepSkills:
+		fields = skillFields(c)
+	case wizardStepStunts:
+		fields = stuntFields(c)
+	case wizardStepDisciplines:
+		fields = disciplineFields(c)
+	}
+	return newCharacterForm(c, fields)
+}
+
+// next completes the current step and moves to the next one.
+// It returns true when the last step is complete and the character can be created.
+func (w *characterWizard) next(username string) bool {
+	if w.step == wizardStepSpirit {
+		// Keep what was entered if the spirit did not change
+		spirit := wizardSpirits[w.spiritIndex].spirit
+		if w.character.Spirit != string(spirit) {
+			w.character = dfm.NewCharacter(spirit)
+		}
+		w.character.Player = username
+		w.character.Group = string(dfm.PC)
+		if w.npc {
+			w.character.Group = string(dfm.NPC)
+		}
+	} else {
+		if count := w.form.errorCount(); count > 0 {
+			w.err = fmt.Errorf("fix the %d invalid field(s) first", count)
+			return false
+		}
+		w.character = w.form.draft
+	}
+
+	w.err = nil
+	steps := w.steps()
+	index := slices.Index(steps, w.step)
+	if index == len(steps)-1 {
+		return true
+	}
+	w.step = steps[index+1]
+	w.form = stepForm(w.step, w.character)
+	return false
+}
+
+// back returns to the previous step, keeping the values of the current step if they are valid
+func (w *characterWizard) back() {
+	if w.step == wizardStepSpirit {
+		return
+	}
+	if w.form.errorCount() == 0 {
+		w.character = w.form.draft
+	}
+
+	w.err = nil
+	steps := w.steps()
+	w.step = steps[slices.Index(steps, w.step)-1]
+	if w.step != wizardStepSpirit {
+		w.form = stepForm(w.step, w.character)
+	}
+}
+
+// updateCharacterWizard handles key presses of the character creation wizard.
+// The wizard takes all keys except Ctrl+C so typing does not switch tabs or quit.
+func (m Model) updateCharacterWizard(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	w := &m.characterWizard
+	if w.creating {
+		return m, nil, msg.String() != "ctrl+c"
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, nil, false
+
+	case "esc":
+		// Discard the new character
+		m.characterViewMode = CharacterViewList
+		return m, nil, true
+
+	case "ctrl+n":
+		if w.next(m.username) {
+			w.creating = true
+			return m, createCharacter(m.username, m.backend, w.character), true
+		}
+		return m, nil, true
+
+	case "ctrl+p":
+		w.back()
+		return m, nil, true
+	}
+
+	if w.step != wizardStepSpirit {
+		w.form = w.form.update(msg)
+		return m, nil, true
+	}
+
+	switch msg.String() {
+	case "up":
+		if w.spiritIndex > 0 {
+			w.spiritIndex--
+		}
+	case "down":
+		if w.spiritIndex < len(wizardSpirits)-1 {
+			w.spiritIndex++
+		}
+	case "t":
+		w.npc = !w.npc
+	case "enter":
+		w.next(m.username)
+	}
+	return m, nil, true
+}
+
+// setCreatedCharacter handles the result of creating a character.
+// On success the wizard closes and the character list is reloaded.
+func (m *Model) setCreatedCharacter(msg characterCreatedMsg) tea.Cmd {
+	m.characterWizard.creating = false
+	if msg.err != nil {
+		m.characterWizard.err = fmt.Errorf("could not create the character: %w", msg.err)
+		return nil
+	}
+	if m.characterViewMode == CharacterViewCreate {
+		m.characterViewMode = CharacterViewList
+	}
+	return loadCharacters(m.username, m.backend)
+}
+
+// renderCharacterWizard renders the current step of the character creation wizard
+func (m Model) renderCharacterWizard() string {
+	w := m.characterWizard
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	currentStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("63")).Padding(0, 1)
+	stepStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Padding(0, 1)
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	// Step bar, e.g. Spirit › Concept › Skills
+	steps := w.steps()
+	var stepNames []string
+	for _, step := range steps {
+		if step == w.step {
+			stepNames = append(stepNames, currentStyle.Render(wizardStepNames[step]))
+		} else {
+			stepNames = append(stepNames, stepStyle.Render(wizardStepNames[step]))
+		}
+	}
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("New Character - Step %d of %d", slices.Index(steps, w.step)+1, len(steps))))
+	lines = append(lines, strings.Join(stepNames, "›"))
+	lines = append(lines, "")
+
+	if w.step == wizardStepSpirit {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Spirit:"))
+		for i, choice := range wizardSpirits {
+			lines = append(lines, renderListItem(strings.Title(string(choice.spirit)), choice.description, w.spiritIndex == i))
+		}
+		lines = append(lines, "")
+		characterType := "Player Character (PC)"
+		if w.npc {
+			characterType = "Non-Player Character (NPC)"
+		}
+		lines = append(lines, fmt.Sprintf("Type: %s %s", characterType, hintStyle.Render("(t: change)")))
+	} else {
+		_, height := m.documentSize(characterWizardHeaderLines)
+		lines = append(lines, w.form.view(height))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case w.creating:
+		lines = append(lines, hintStyle.Render("Creating..."))
+	case w.err != nil:
+		lines = append(lines, errorStyle.Render(w.err.Error()))
+	case w.step != wizardStepSpirit && w.form.errorCount() > 0:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("%d invalid field(s)", w.form.errorCount())))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,19 +1,197 @@
 package ui
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdb"
 	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
 )
 
+// characterChangedMsg is sent when characters have been created, updated or deleted by anyone
+type characterChangedMsg struct{}
+
+// charactersRefreshedMsg is sent when the characters have been reloaded after a change
+type charactersRefreshedMsg struct {
+	characters []dfm.Character
+	err        error
+}
+
+// waitForCharacterChange waits for the next character change.
+// Changes arriving together are reported as one message.
+// It returns nil when there is no subscription or it has ended.
+func waitForCharacterChange(changes <-chan dfdb.ChangeEvent) tea.Cmd {
+	if changes == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		for {
+			select {
+			case _, ok := <-changes:
+				if !ok {
+					return characterChangedMsg{}
+				}
+			default:
+				return characterChangedMsg{}
+			}
+		}
+	}
+}
+
+// refreshCharacters reloads the characters of the user after a change
+func refreshCharacters(username string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		characters, err := backend.GetUserCharacters(username)
+		return charactersRefreshedMsg{characters: characters, err: err}
+	}
+}
+
+// setCharacters replaces the character list in place, keeping the selected character,
+// the character shown in the detail view and the Fate Tracker character selected.
+// If the character shown in the detail view is gone, the list view is shown.
+func (m *Model) setCharacters(characters []dfm.Character) {
+	idAt := func(index int) string {
+		if index >= 0 && index < len(m.characters) {
+			return m.characters[index].ID
+		}
+		return ""
+	}
+	selectedID := ""
+	if i := m.selectedCharacterInList(); i >= 0 {
+		selectedID = m.characters[i].ID
+	}
+	fateID := idAt(m.fateCharacterIndex)
+
+	m.characters = characters
+	m.selectedCharacterIndex = indexOfCharacter(m.listedCharacters(), selectedID, 0)
+	m.fateCharacterIndex = indexOfCharacter(characters, fateID, 0)
+	if idAt(m.fateCharacterIndex) != fateID {
+		// Another character is selected, its skills differ
+		m.fateSkillIndex = -1
+	}
+
+	if m.selectedCharacter != nil {
+		if i := indexOfCharacter(characters, m.selectedCharacter.ID, -1); i >= 0 {
+			m.selectedCharacter = &m.characters[i]
+		} else if m.characterViewMode == CharacterViewDetail {
+			m.selectedCharacter = nil
+			m.characterViewMode = CharacterViewList
+		}
+	}
+}
+
+// indexOfCharacter returns the index of a character by ID, or fallback if it is
+// not found. The fallback is -1 for an empty list.
+func indexOfCharacter(characters []dfm.Character, id string, fallback int) int {
+	for i, character := range characters {
+		if character.ID == id {
+			return i
+		}
+	}
+	if len(characters) == 0 {
+		return -1
+	}
+	return fallback
+}
+
+// characterDetailHeaderLines is the number of lines above the character sheet in the detail view
+const characterDetailHeaderLines = 3
+
+// characterSorts are the sort orders of the character list, in the order "o" cycles through them
+var characterSorts = []struct {
+	sort dfm.CharacterSort
+	name string
+}{
+	{dfm.SortByName, "name"},
+	{dfm.SortByEmbraceYear, "embrace year"},
+	{dfm.SortByModified, "last modified"},
+}
+
+// listedCharacters returns the characters shown in the list view: those matching the
+// search and filters, player characters first, each group in the order of the character query.
+func (m Model) listedCharacters() []dfm.Character {
+	listed := m.characterQuery.Apply(m.characters)
+	if text := m.characterSearch.text; text != "" {
+		listed = slices.DeleteFunc(listed, func(c dfm.Character) bool {
+			return !matchesCharacterSearch(c, text)
+		})
+	}
+	slices.SortStableFunc(listed, func(a, b dfm.Character) int {
+		return cmp.Compare(groupOrder(a), groupOrder(b))
+	})
+	return listed
+}
+
+// groupOrder is the position of the group of a character in the list view
+func groupOrder(c dfm.Character) int {
+	switch c.Group {
+	case string(dfm.PC):
+		return 0
+	case string(dfm.NPC):
+		return 1
+	}
+	return 2
+}
+
+// selectedCharacterInList returns the index in m.characters of the character selected
+// in the list view, or -1 if none is selected.
+func (m Model) selectedCharacterInList() int {
+	listed := m.listedCharacters()
+	if m.selectedCharacterIndex < 0 || m.selectedCharacterIndex >= len(listed) {
+		return -1
+	}
+	return indexOfCharacter(m.characters, listed[m.selectedCharacterIndex].ID, -1)
+}
+
+// cycleCharacterSort switches the list view to the next sort order, keeping the selected character
+func (m *Model) cycleCharacterSort() {
+	m.keepCharacterSelection(func() {
+		next := 0
+		for i, s := range characterSorts {
+			if s.sort == m.characterQuery.Sort || s.sort == dfm.SortByName && m.characterQuery.Sort == "" {
+				next = (i + 1) % len(characterSorts)
+			}
+		}
+		m.characterQuery.Sort = characterSorts[next].sort
+		// Most recently changed characters are the interesting ones
+		m.characterQuery.Descending = m.characterQuery.Sort == dfm.SortByModified
+	})
+}
+
+// characterSortName returns the display name of the sort order of the list view
+func (m Model) characterSortName() string {
+	for _, s := range characterSorts {
+		if s.sort == m.characterQuery.Sort {
+			return s.name
+		}
+	}
+	return characterSorts[0].name
+}
+
 // renderCharactersTab renders the Characters tab content
 func (m Model) renderCharactersTab() string {
 	// Switch between list and detail view based on current view mode
 	if m.characterViewMode == CharacterViewDetail {
 		return m.renderCharacterDetail()
 	}
+	if m.characterViewMode == CharacterViewEdit {
+		return m.renderCharacterEditor()
+	}
+	if m.characterViewMode == CharacterViewCreate {
+		return m.renderCharacterWizard()
+	}
+	if m.characterViewMode == CharacterViewHistory {
+		return m.renderCharacterHistory()
+	}
 
 	// List view
 	if m.err != nil {
@@ -25,17 +203,30 @@ func (m Model) renderCharactersTab() string {
 	}
 
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Characters for %s:", m.username)))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Characters for %s:", m.username))+
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(fmt.Sprintf(" (sorted by %s)", m.characterSortName())))
+	if filters := m.renderCharacterFilters(); filters != "" {
+		lines = append(lines, filters)
+	}
 	lines = append(lines, "")
 
-	// Separate PCs and NPCs
+	listed := m.listedCharacters()
+	if len(listed) == 0 {
+		lines = append(lines, "No characters match the search and filters")
+		return strings.Join(lines, "\n")
+	}
+
+	// Separate PCs, NPCs and characters without a valid group, in the order of listedCharacters
 	var pcs []dfm.Character
 	var npcs []dfm.Character
-	for _, char := range m.characters {
+	var others []dfm.Character
+	for _, char := range listed {
 		if char.Group == string(dfm.PC) {
 			pcs = append(pcs, char)
 		} else if char.Group == string(dfm.NPC) {
 			npcs = append(npcs, char)
+		} else {
+			others = append(others, char)
 		}
 	}
 
@@ -59,23 +250,54 @@ func (m Model) renderCharactersTab() string {
 		}
 	}
 
+	// Render the characters whose group is neither PC nor NPC, so that every selectable character is shown
+	if len(others) > 0 {
+		if len(npcs) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("14")).Render("Other Characters:"))
+		startIndex := len(pcs) + len(npcs)
+		for i, char := range others {
+			line := renderCharacter(char, m.selectedCharacterIndex == startIndex+i)
+			lines = append(lines, line)
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
-// renderCharacterDetail renders the detailed view of a selected character
+// renderCharacterDetail renders the detailed view of a selected character.
+// The character sheet scrolls below the title and the play status.
 func (m Model) renderCharacterDetail() string {
 	if m.selectedCharacter == nil {
 		return "No character selected"
 	}
 
-	char := m.selectedCharacter
-
-	// Create styles for the detail view
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("15")).
-		MarginBottom(1)
+		Foreground(lipgloss.Color("15"))
+
+	vp := m.characterDetailViewport()
+	header := fmt.Sprintf("%s  %s", titleStyle.Render("Character Details"), renderScrollIndicator(vp))
+	return strings.Join([]string{header, m.renderCharacterPlayStatus(), "", vp.View()}, "\n")
+}
+
+// characterDetailViewport returns the viewport of the character detail view, sized to
+// the window and holding the current character sheet at the scroll position of the view.
+func (m Model) characterDetailViewport() viewport.Model {
+	width, height := m.documentSize(characterDetailHeaderLines)
+	vp := m.characterViewport
+	vp.Width = width
+	vp.Height = height
+	vp.SetContent(lipgloss.NewStyle().Width(width).Render(m.renderCharacterSheet()))
+	return vp
+}
 
+// renderCharacterSheet renders the full character sheet of the selected character
+func (m Model) renderCharacterSheet() string {
+	char := m.selectedCharacter
+
+	// Create styles for the detail view
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("12")).
@@ -98,12 +320,8 @@ func (m Model) renderCharacterDetail() string {
 			Render("Non-Player Character (NPC)")
 	}
 
-	// Build the detail view
-	var lines []string
-	lines = append(lines, titleStyle.Render("Character Details"))
-	lines = append(lines, "")
-
 	// Basic information
+	var lines []string
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Basic Information:"))
 	lines = append(lines, fmt.Sprintf("%s %s",
 		labelStyle.Render("Name:"),
@@ -159,17 +377,7 @@ func (m Model) renderCharacterDetail() string {
 		lines = append(lines, "")
 		lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Aspects:"))
 		for _, aspect := range char.Aspects {
-			aspectType := aspect.Type
-			if aspectType == "high concept" {
-				aspectType = "High Concept"
-			} else if aspectType == "trouble" {
-				aspectType = "Trouble"
-			} else if aspectType == "clan" {
-				aspectType = "Clan"
-			} else if aspectType == "covenant" {
-				aspectType = "Covenant"
-			}
-			lines = append(lines, fmt.Sprintf("  %s: %s", aspectType, aspect.Title))
+			lines = append(lines, fmt.Sprintf("  %s: %s", aspectTypeName(aspect.Type), aspect.Title))
 			if aspect.Description != "" {
 				lines = append(lines, fmt.Sprintf("    %s", aspect.Description))
 			}
@@ -279,45 +487,29 @@ func (m Model) renderCharacterDetail() string {
 		lines = append(lines, valueStyle.Render(char.Notes))
 	}
 
-	lines = append(lines, "")
-	lines = append(lines, lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		Render("Press ESC to return to character list"))
-
 	return strings.Join(lines, "\n")
 }
 
 // renderCharacter renders a single character line with description and optional selection highlight
 func renderCharacter(char dfm.Character, isSelected bool) string {
-	nameStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("15"))
-
-	// Add selection indicator and highlight
-	cursor := "  "
-	if isSelected {
-		cursor = "> "
-		// Highlight selected character with background color
-		nameStyle = nameStyle.Background(lipgloss.Color("237"))
-	}
+	return renderListItem(char.Name, char.Description, isSelected)
+}
 
-	// Prepare description - truncate to 50 characters if needed
-	description := char.Description
-	if len(description) > 50 {
-		description = description[:50] + "..."
+// aspectTypeName returns the display name of an aspect type, e.g. "High Concept"
+func aspectTypeName(aspectType string) string {
+	switch aspectType {
+	case "high concept":
+		return "High Concept"
+	case "trouble":
+		return "Trouble"
+	case "clan":
+		return "Clan"
+	case "covenant":
+		return "Covenant"
+	case "relationship":
+		return "Relationship"
+	case "free":
+		return "Free"
 	}
-
-	// Format: cursor + name + " - " + description
-	var result string
-	if description != "" {
-		result = fmt.Sprintf("%s%s - %s",
-			cursor,
-			nameStyle.Render(char.Name),
-			lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(description))
-	} else {
-		result = fmt.Sprintf("%s%s",
-			cursor,
-			nameStyle.Render(char.Name))
-	}
-
-	return result
+	return aspectType
 }
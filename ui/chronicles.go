@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// chronicleHeaderLines is the number of lines above the README in the chronicle detail view
+const chronicleHeaderLines = 3
+
+// chroniclesLoadedMsg is sent when chronicles are loaded from backend
+type chroniclesLoadedMsg struct {
+	chronicles []dfm.Chronicle
+	err        error
+}
+
+// chronicleReadmeLoadedMsg is sent when a chronicle's README and resources are loaded from backend
+type chronicleReadmeLoadedMsg struct {
+	chronicleID  string
+	readme       string
+	err          error
+	resources    []dfm.Resource
+	resourcesErr error
+}
+
+// loadChronicles loads the user's chronicles from the backend
+func loadChronicles(username string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		chronicles, err := backend.GetUserChronicles(username)
+		return chroniclesLoadedMsg{
+			chronicles: chronicles,
+			err:        err,
+		}
+	}
+}
+
+// loadChronicleReadme loads a chronicle's README and downloadable resources from the backend
+func loadChronicleReadme(username, chronicleID string, backend services.Backend) tea.Cmd {
+	return func() tea.Msg {
+		readme, err := backend.GetChronicleReadme(username, chronicleID)
+		resources, resourcesErr := backend.GetChronicleResources(username, chronicleID)
+		return chronicleReadmeLoadedMsg{
+			chronicleID:  chronicleID,
+			readme:       readme,
+			err:          err,
+			resources:    resources,
+			resourcesErr: resourcesErr,
+		}
+	}
+}
+
+// updateChronicles handles key presses specific to the Chronicles tab.
+// It returns false if the key was not handled so global keys keep working.
+func (m Model) updateChronicles(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.chronicleViewMode == ChronicleViewDetail {
+		if msg.String() == "esc" {
+			// Return to list view
+			m.chronicleViewMode = ChronicleViewList
+			m.selectedChronicle = nil
+			return m, nil, true
+		}
+
+		// Scroll the README
+		vp, handled := updateDocumentViewport(m.chronicleViewport, msg)
+		m.chronicleViewport = vp
+		return m, nil, handled
+	}
+
+	switch msg.String() {
+	case "up":
+		if m.selectedChronicleIndex > 0 {
+			m.selectedChronicleIndex--
+		}
+		return m, nil, true
+
+	case "down":
+		if m.selectedChronicleIndex < len(m.chronicles)-1 {
+			m.selectedChronicleIndex++
+		}
+		return m, nil, true
+
+	case "enter":
+		// Open the chronicle and load its README
+		if m.selectedChronicleIndex >= 0 && m.selectedChronicleIndex < len(m.chronicles) {
+			m.selectedChronicle = &m.chronicles[m.selectedChronicleIndex]
+			m.chronicleViewMode = ChronicleViewDetail
+			m.chronicleReadme = ""
+			m.chronicleReadmeErr = nil
+			m.chronicleViewport.SetContent("Loading README...")
+			m.chronicleViewport.GotoTop()
+			return m, loadChronicleReadme(m.username, m.selectedChronicle.ID, m.backend), true
+		}
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// setChronicleReadme renders the loaded README and downloads into the chronicle viewport
+func (m *Model) setChronicleReadme() {
+	width, height := m.documentSize(chronicleHeaderLines)
+	m.chronicleViewport.Width = width
+	m.chronicleViewport.Height = height
+
+	readme := m.chronicleReadme
+	switch {
+	case errors.Is(m.chronicleReadmeErr, dfdb.ErrDocumentNotFound):
+		readme = "*This chronicle has no README.*"
+	case m.chronicleReadmeErr != nil:
+		m.chronicleViewport.SetContent(fmt.Sprintf("Error loading README: %v", m.chronicleReadmeErr))
+		return
+	}
+
+	chronicleID := m.selectedChronicle.ID
+	downloads := m.downloadsMarkdown(m.chronicleResources, m.chronicleResourcesErr, func(name string) string {
+		return services.ChronicleResourcePath(chronicleID, name)
+	})
+	m.chronicleViewport.SetContent(renderMarkdown(readme+downloads, width))
+}
+
+// renderChroniclesTab renders the Chronicles tab content
+func (m Model) renderChroniclesTab() string {
+	if m.chronicleViewMode == ChronicleViewDetail {
+		return m.renderChronicleDetail()
+	}
+
+	// List view
+	if m.chroniclesErr != nil {
+		return fmt.Sprintf("Error loading chronicles: %v", m.chroniclesErr)
+	}
+
+	if len(m.chronicles) == 0 {
+		return "No chronicles found"
+	}
+
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Chronicles for %s:", m.username)))
+	lines = append(lines, "")
+
+	for i, chronicle := range m.chronicles {
+		lines = append(lines, renderListItem(chronicle.Name, chronicle.Description, m.selectedChronicleIndex == i))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderChronicleDetail renders the selected chronicle with its README
+func (m Model) renderChronicleDetail() string {
+	if m.selectedChronicle == nil {
+		return "No chronicle selected"
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("15"))
+
+	header := fmt.Sprintf("%s  %s", titleStyle.Render(m.selectedChronicle.Name), renderScrollIndicator(m.chronicleViewport))
+	gm := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Gamemaster: %s", m.selectedChronicle.Gamemaster))
+
+	return strings.Join([]string{header, gm, "", m.chronicleViewport.View()}, "\n")
+}
+
+// renderListItem renders a selectable list line with name, optional description and selection highlight
+func renderListItem(name, description string, isSelected bool) string {
+	nameStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15"))
+
+	cursor := "  "
+	if isSelected {
+		cursor = "> "
+		nameStyle = nameStyle.Background(lipgloss.Color("237"))
+	}
+
+	if len(description) > 50 {
+		description = description[:50] + "..."
+	}
+
+	if description == "" {
+		return cursor + nameStyle.Render(name)
+	}
+	return fmt.Sprintf("%s%s - %s",
+		cursor,
+		nameStyle.Render(name),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(description))
+}
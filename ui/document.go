@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfm"
+)
+
+// newDocumentViewport creates a viewport for scrollable documents such as READMEs.
+// Left and right are left to tab navigation.
+func newDocumentViewport() viewport.Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap.Left.SetEnabled(false)
+	vp.KeyMap.Right.SetEnabled(false)
+	return vp
+}
+
+// updateDocumentViewport scrolls a document viewport with pager keys.
+// It returns false if the key is not a scrolling key.
+func updateDocumentViewport(vp viewport.Model, msg tea.KeyMsg) (viewport.Model, bool) {
+	switch msg.String() {
+	case "home", "g":
+		vp.GotoTop()
+		return vp, true
+	case "end", "G":
+		vp.GotoBottom()
+		return vp, true
+	}
+
+	km := vp.KeyMap
+	if key.Matches(msg, km.PageDown, km.PageUp, km.HalfPageDown, km.HalfPageUp, km.Down, km.Up) {
+		vp, _ = vp.Update(msg)
+		return vp, true
+	}
+	return vp, false
+}
+
+// documentSize returns the width and height available for a document
+// shown in the content area below the given number of header lines.
+func (m Model) documentSize(headerLines int) (int, int) {
+	// Content area is m.width-4 wide and m.height-10 high, with padding 1, 2
+	width := m.width - 8
+	height := m.height - 12 - headerLines
+	if width < 20 {
+		width = 20
+	}
+	if height < 3 {
+		height = 3
+	}
+	return width, height
+}
+
+// renderMarkdown renders Markdown as styled terminal text wrapped to width.
+// If rendering fails, the Markdown source is returned as-is.
+func renderMarkdown(markdown string, width int) string {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return markdown
+	}
+
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		return markdown
+	}
+	return rendered
+}
+
+// renderScrollIndicator renders the scroll position of a viewport, e.g. "Lines 1-20 of 84 (24%)"
+func renderScrollIndicator(vp viewport.Model) string {
+	total := vp.TotalLineCount()
+	first := vp.YOffset + 1
+	last := vp.YOffset + vp.VisibleLineCount()
+	if total == 0 {
+		first = 0
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Lines %d-%d of %d (%3.f%%)", first, last, total, vp.ScrollPercent()*100))
+}
+
+// downloadsMarkdown renders downloadable resource files as a Markdown section
+// listing each file with its size and the scp command to fetch it.
+// resourcePath returns the download path of a file by name.
+func (m Model) downloadsMarkdown(resources []dfm.Resource, err error, resourcePath func(name string) string) string {
+	if err != nil {
+		return fmt.Sprintf("\n\n## Downloads\n\n*Error loading downloads: %v*\n", err)
+	}
+	if len(resources) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Downloads\n\n")
+	for _, resource := range resources {
+		fmt.Fprintf(&b, "- **%s** (%s)\n\n  `%s`\n\n", resource.Name, formatSize(resource.Size), m.scpCommand(resourcePath(resource.Name)))
+	}
+	return b.String()
+}
+
+// scpCommand returns the command for downloading a resource into the current directory.
+// -O selects the SCP protocol, which newer OpenSSH clients no longer use by default.
+func (m Model) scpCommand(resourcePath string) string {
+	host, port, err := net.SplitHostPort(m.serverAddr)
+	if err != nil {
+		host, port = m.serverAddr, "22"
+	}
+
+	source := fmt.Sprintf("%s@%s:%s", m.username, host, resourcePath)
+	if strings.ContainsAny(source, " '\"\\$`!*?&;|<>()") {
+		source = "'" + strings.ReplaceAll(source, "'", `'\''`) + "'"
+	}
+
+	if port == "22" {
+		return fmt.Sprintf("scp -O %s .", source)
+	}
+	return fmt.Sprintf("scp -O -P %s %s .", port, source)
+}
+
+// formatSize formats a file size in bytes, e.g. "512 B" or "1.4 MB"
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TB", value)
+}
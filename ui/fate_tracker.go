@@ -0,0 +1,430 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdice"
+	"github.com/hkionline/dftui/dflib/dfm"
+	"github.com/hkionline/dftui/services"
+)
+
+// rollEntry is a single roll in the Fate Tracker history
+type rollEntry struct {
+	Username  string        // User who rolled
+	Character string        // Name of the character used for the roll, if any
+	Result    dfdice.Result // Dice roll result
+}
+
+// sessionEventMsg is sent when another user in the same game session causes an event
+type sessionEventMsg services.SessionEvent
+
+// sessionLogSavedMsg is sent when an entry has been stored in the session Fate tracker log
+type sessionLogSavedMsg struct {
+	err error
+}
+
+// saveSessionLog stores an entry in the Fate tracker log of a game session
+func saveSessionLog(username string, backend services.Backend, sessionID string, entry dfm.TrackerEntry) tea.Cmd {
+	return func() tea.Msg {
+		return sessionLogSavedMsg{err: backend.AppendSessionLog(username, sessionID, entry)}
+	}
+}
+
+// trackerEntryFromRoll converts a roll into a session Fate tracker log entry
+func trackerEntryFromRoll(entry rollEntry) dfm.TrackerEntry {
+	result := entry.Result
+	dice := make([]int, len(result.Dice))
+	for i, face := range result.Dice {
+		dice[i] = int(face)
+	}
+	return dfm.TrackerEntry{
+		Type:        dfm.TrackerEntryRoll,
+		Time:        result.RolledAt,
+		Username:    entry.Username,
+		Character:   entry.Character,
+		Dice:        dice,
+		Skill:       result.Skill,
+		SkillRating: result.Bonus,
+		Modifier:    result.Modifier,
+		Total:       result.Total(),
+	}
+}
+
+// turnName returns the name shown for whose turn it is
+func turnName(username, character string) string {
+	if character != "" {
+		return character
+	}
+	return username
+}
+
+// waitForSessionEvent waits for the next event from the session hub.
+// It returns nil when there is no session client or the client has been closed.
+func waitForSessionEvent(session *services.SessionClient) tea.Cmd {
+	if session == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		event, ok := <-session.Events()
+		if !ok {
+			return nil
+		}
+		return sessionEventMsg(event)
+	}
+}
+
+// updateFateTracker handles key presses specific to the Fate Tracker tab.
+// It returns false if the key was not handled so global keys keep working.
+func (m Model) updateFateTracker(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	// The game session prompt captures all keys while open
+	if m.sessionInputActive {
+		m, cmd := m.updateSessionInput(msg)
+		return m, cmd, true
+	}
+
+	switch msg.String() {
+	case "r", " ", "enter":
+		// Roll 4dF with the selected skill
+		skill, bonus := m.fateSkill()
+		result := m.roller.Roll(skill, bonus)
+		result.Modifier = m.fateModifier
+		entry := rollEntry{
+			Username: m.username,
+			Result:   result,
+		}
+		if char := m.fateCharacter(); char != nil {
+			entry.Character = char.Name
+		}
+		m.rollHistory = append([]rollEntry{entry}, m.rollHistory...)
+		m.rollHistoryOffset = 0
+
+		// Share the roll with the other users in session-mode and store it in the session log
+		if sessionID := m.sessionID(); sessionID != "" {
+			m.session.Publish(services.SessionEvent{
+				Type:      services.SessionEventRoll,
+				Character: entry.Character,
+				Roll:      entry.Result,
+			})
+			return m, saveSessionLog(m.username, m.backend, sessionID, trackerEntryFromRoll(entry)), true
+		}
+		return m, nil, true
+
+	case "t":
+		// Take the turn in session-mode with the selected character
+		sessionID := m.sessionID()
+		if sessionID == "" {
+			return m, nil, true
+		}
+		character := ""
+		if char := m.fateCharacter(); char != nil {
+			character = char.Name
+		}
+		m.currentTurn = turnName(m.username, character)
+		m.session.Publish(services.SessionEvent{
+			Type:      services.SessionEventTurn,
+			Character: character,
+		})
+		return m, saveSessionLog(m.username, m.backend, sessionID, dfm.TrackerEntry{
+			Type:      dfm.TrackerEntryTurn,
+			Time:      time.Now(),
+			Username:  m.username,
+			Character: character,
+			Turn:      m.currentTurn,
+		}), true
+
+	case "j":
+		// Open the prompt for joining a game session
+		if m.session != nil {
+			m.sessionInputActive = true
+			m.sessionInput = ""
+		}
+		return m, nil, true
+
+	case "l":
+		// Leave the game session and return to single-mode
+		if m.session != nil {
+			m.session.Leave()
+		}
+		m.currentTurn = ""
+		return m, nil, true
+
+	case "up":
+		// Select previous skill (-1 means no skill)
+		if m.fateSkillIndex > -1 {
+			m.fateSkillIndex--
+		}
+		return m, nil, true
+
+	case "down":
+		// Select next skill of the selected character
+		if char := m.fateCharacter(); char != nil && m.fateSkillIndex < len(char.Skills)-1 {
+			m.fateSkillIndex++
+		}
+		return m, nil, true
+
+	case "+", "=":
+		// Raise the modifier, e.g. for an invoke
+		m.fateModifier++
+		return m, nil, true
+
+	case "-":
+		// Lower the modifier
+		m.fateModifier--
+		return m, nil, true
+
+	case "c":
+		// Cycle through the user's characters, including "no character"
+		if len(m.characters) > 0 {
+			m.fateCharacterIndex++
+			if m.fateCharacterIndex >= len(m.characters) {
+				m.fateCharacterIndex = -1
+			}
+			m.fateSkillIndex = -1
+		}
+		return m, nil, true
+
+	case "pgup":
+		// Scroll roll history towards newer rolls
+		m.rollHistoryOffset -= m.rollHistoryRows()
+		if m.rollHistoryOffset < 0 {
+			m.rollHistoryOffset = 0
+		}
+		return m, nil, true
+
+	case "pgdown":
+		// Scroll roll history towards older rolls
+		maxOffset := len(m.rollHistory) - m.rollHistoryRows()
+		if maxOffset < 0 {
+			maxOffset = 0
+		}
+		m.rollHistoryOffset += m.rollHistoryRows()
+		if m.rollHistoryOffset > maxOffset {
+			m.rollHistoryOffset = maxOffset
+		}
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// updateSessionInput handles typing in the game session prompt
+func (m Model) updateSessionInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.sessionInputActive = false
+		sessionID := strings.TrimSpace(m.sessionInput)
+		if sessionID != "" && sessionID != m.session.SessionID() {
+			return m, joinSession(m.username, m.session, m.backend, sessionID)
+		}
+	case tea.KeyEsc:
+		m.sessionInputActive = false
+	case tea.KeyBackspace:
+		if runes := []rune(m.sessionInput); len(runes) > 0 {
+			m.sessionInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.sessionInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// fateCharacter returns the character whose skills are used for rolls, or nil if none
+func (m Model) fateCharacter() *dfm.Character {
+	if m.fateCharacterIndex < 0 || m.fateCharacterIndex >= len(m.characters) {
+		return nil
+	}
+	return &m.characters[m.fateCharacterIndex]
+}
+
+// fateSkill returns the selected skill title and rating, or an empty title for a plain roll
+func (m Model) fateSkill() (string, int) {
+	char := m.fateCharacter()
+	if char == nil || m.fateSkillIndex < 0 || m.fateSkillIndex >= len(char.Skills) {
+		return "", 0
+	}
+	skill := char.Skills[m.fateSkillIndex]
+	return skill.Title, skill.Rating
+}
+
+// rollHistoryRows returns how many history rows fit in the content area
+func (m Model) rollHistoryRows() int {
+	rows := m.height - 26
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+// renderFateTrackerTab renders the Fate Tracker tab content
+func (m Model) renderFateTrackerTab() string {
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("12")).
+		Width(11)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241"))
+
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Fate Tracker"))
+	if sessionID := m.sessionID(); sessionID != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(
+			fmt.Sprintf("Session-mode: %s", sessionID)))
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("Rolls are shared with: %s",
+			strings.Join(m.session.Participants(), ", "))))
+		turn := m.currentTurn
+		if turn == "" {
+			turn = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			labelStyle.Render("Turn:"),
+			valueStyle.Render(turn),
+			hintStyle.Render("(t: take turn)")))
+		if m.fateErr != nil {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(
+				fmt.Sprintf("Failed to store session log: %v", m.fateErr)))
+		}
+	} else {
+		lines = append(lines, hintStyle.Render("Single-mode: rolls are visible only to you and are not stored"))
+	}
+	if m.sessionJoinErr != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(
+			fmt.Sprintf("Failed to join session: %v", m.sessionJoinErr)))
+	}
+	if m.sessionInputActive {
+		lines = append(lines, fmt.Sprintf("%s %s█ %s",
+			labelStyle.Render("Join:"),
+			valueStyle.Render(m.sessionInput),
+			hintStyle.Render("(Enter: join, Esc: cancel)")))
+	}
+	lines = append(lines, "")
+
+	// Character and skill used for the roll
+	charName := "None"
+	if char := m.fateCharacter(); char != nil {
+		charName = char.Name
+	}
+	lines = append(lines, fmt.Sprintf("%s %s %s",
+		labelStyle.Render("Character:"),
+		valueStyle.Render("< "+charName+" >"),
+		hintStyle.Render("(c: change)")))
+
+	skillName := "None"
+	if title, rating := m.fateSkill(); title != "" {
+		skillName = fmt.Sprintf("%s %+d", strings.Title(title), rating)
+	}
+	lines = append(lines, fmt.Sprintf("%s %s %s",
+		labelStyle.Render("Skill:"),
+		valueStyle.Render("< "+skillName+" >"),
+		hintStyle.Render("(↑/↓: change)")))
+	lines = append(lines, fmt.Sprintf("%s %s %s",
+		labelStyle.Render("Modifier:"),
+		valueStyle.Render(fmt.Sprintf("< %+d >", m.fateModifier)),
+		hintStyle.Render("(+/-: change)")))
+
+	// Latest roll
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Last Roll:"))
+	if len(m.rollHistory) == 0 {
+		lines = append(lines, hintStyle.Render("  Press r to roll 4dF"))
+	} else {
+		last := m.rollHistory[0].Result
+		if m.rollHistory[0].Username != m.username {
+			lines = append(lines, "  "+hintStyle.Render(rollAuthor(m.rollHistory[0])))
+		}
+		lines = append(lines, "  "+renderDice(last.Dice))
+		lines = append(lines, fmt.Sprintf("  Dice %+d  Skill %+d  Modifier %+d  =  %s",
+			last.DiceTotal(),
+			last.Bonus,
+			last.Modifier,
+			renderLadder(last.Total())))
+	}
+
+	// Scrollable roll history
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Roll History:"))
+	if len(m.rollHistory) == 0 {
+		lines = append(lines, hintStyle.Render("  No rolls yet"))
+		return strings.Join(lines, "\n")
+	}
+
+	start := m.rollHistoryOffset
+	end := start + m.rollHistoryRows()
+	if end > len(m.rollHistory) {
+		end = len(m.rollHistory)
+	}
+	for _, entry := range m.rollHistory[start:end] {
+		lines = append(lines, "  "+renderRollLine(entry))
+	}
+	lines = append(lines, hintStyle.Render(fmt.Sprintf("  Showing %d-%d of %d rolls", start+1, end, len(m.rollHistory))))
+
+	return strings.Join(lines, "\n")
+}
+
+// renderDice renders the four dice faces with colors
+func renderDice(dice [dfdice.DiceCount]dfdice.Face) string {
+	plusStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	minusStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("9"))
+	blankStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	var faces []string
+	for _, face := range dice {
+		style := blankStyle
+		if face == dfdice.Plus {
+			style = plusStyle
+		} else if face == dfdice.Minus {
+			style = minusStyle
+		}
+		faces = append(faces, "["+style.Render(face.String())+"]")
+	}
+	return strings.Join(faces, " ")
+}
+
+// renderLadder renders a total with its Fate ladder name
+func renderLadder(total int) string {
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("15")).
+		Render(fmt.Sprintf("%+d %s", total, dfdice.LadderName(total)))
+}
+
+// sessionID returns the current game session ID, or an empty string in single-mode
+func (m Model) sessionID() string {
+	if m.session == nil {
+		return ""
+	}
+	return m.session.SessionID()
+}
+
+// rollAuthor describes who made a roll, e.g. "alice (Victor Joki)"
+func rollAuthor(entry rollEntry) string {
+	if entry.Character == "" {
+		return entry.Username
+	}
+	return fmt.Sprintf("%s (%s)", entry.Username, entry.Character)
+}
+
+// renderRollLine renders a single roll history entry
+func renderRollLine(entry rollEntry) string {
+	result := entry.Result
+	skill := "No skill"
+	if result.Skill != "" {
+		skill = fmt.Sprintf("%s %+d", strings.Title(result.Skill), result.Bonus)
+	}
+	if result.Modifier != 0 {
+		skill += fmt.Sprintf(" mod %+d", result.Modifier)
+	}
+	return fmt.Sprintf("%s  %-24s %s  %-16s → %s",
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(result.RolledAt.Format("15:04:05")),
+		rollAuthor(entry),
+		result.FacesString(),
+		skill,
+		renderLadder(result.Total()))
+}
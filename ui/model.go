@@ -3,8 +3,11 @@ package ui
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/dflib/dfdb"
+	"github.com/hkionline/dftui/dflib/dfdice"
 	"github.com/hkionline/dftui/dflib/dfm"
 	"github.com/hkionline/dftui/services"
 )
@@ -26,6 +29,27 @@ type CharacterViewMode int
 const (
 	CharacterViewList CharacterViewMode = iota
 	CharacterViewDetail
+	CharacterViewEdit    // Character editor form
+	CharacterViewCreate  // Character creation wizard
+	CharacterViewHistory // Character history or deleted characters
+)
+
+// ChronicleViewMode represents the current view mode in the Chronicles tab
+type ChronicleViewMode int
+
+const (
+	ChronicleViewList ChronicleViewMode = iota
+	ChronicleViewDetail
+)
+
+// CampaignViewMode represents the current view mode in the Campaigns tab
+type CampaignViewMode int
+
+const (
+	CampaignViewList    CampaignViewMode = iota
+	CampaignViewDetail                   // README entry and session list of a campaign
+	CampaignViewReadme                   // Campaign README
+	CampaignViewSession                  // Session notes and Fate tracker log
 )
 
 // TabInfo holds display information for tabs
@@ -46,40 +70,136 @@ var tabs = []TabInfo{
 // It follows the Elm architecture: Model -> Update -> View
 // See: https://github.com/charmbracelet/bubbletea
 type Model struct {
-	username               string
-	activeTab              Tab
-	characters             []dfm.Character
-	backend                services.Backend
-	err                    error
-	width                  int
-	height                 int
-	selectedCharacterIndex int               // Index of currently selected character in list (0-based, -1 if none)
-	characterViewMode      CharacterViewMode // Current view mode in Characters tab (list or detail)
-	selectedCharacter      *dfm.Character    // Currently selected character for detail view
+	username                   string
+	serverAddr                 string // Host and port of the SSH server, used in download commands
+	activeTab                  Tab
+	characters                 []dfm.Character
+	backend                    services.Backend
+	err                        error
+	width                      int
+	height                     int
+	selectedCharacterIndex     int                     // Index of currently selected character in the listed characters (0-based, -1 if none)
+	characterQuery             dfm.CharacterQuery      // Sort order and filters of the character list
+	characterSearch            characterSearch         // Search text of the character list
+	characterViewMode          CharacterViewMode       // Current view mode in Characters tab (list or detail)
+	selectedCharacter          *dfm.Character          // Currently selected character for detail view
+	characterViewport          viewport.Model          // Scroll position of the character sheet in the detail view
+	characterEditor            characterForm           // Form of the character being edited
+	characterSaving            bool                    // Whether the edited character is being saved
+	characterSaveErr           error                   // Error from saving the edited character
+	characterWizard            characterWizard         // State of the character creation wizard
+	consequencePrompt          consequencePrompt       // Consequence being taken or recovered in the detail view
+	characterConflict          characterConflict       // Conflict with a change saved by someone else while editing
+	characterHistory           characterHistory        // History of the selected character or the deleted characters
+	characterChanges           <-chan dfdb.ChangeEvent // Character changes made by anyone (nil if unavailable)
+	roller                     *dfdice.Roller          // Fate dice roller for the Fate Tracker tab
+	rollHistory                []rollEntry             // Roll history of this SSH session, newest first
+	rollHistoryOffset          int                     // Scroll offset of the roll history
+	fateCharacterIndex         int                     // Index of the character used for skill bonuses (-1 if none)
+	fateSkillIndex             int                     // Index of the selected skill of that character (-1 if none)
+	fateModifier               int                     // Modifier added to the rolls, such as invokes
+	session                    *services.SessionClient // Session hub client for session-mode rolls (nil if unavailable)
+	sessionInput               string                  // Game session ID being typed in the Fate Tracker tab
+	sessionInputActive         bool                    // Whether the game session ID prompt is open
+	currentTurn                string                  // Name of the character or user whose turn it is in session-mode
+	sessionJoinErr             error                   // Error from joining a game session
+	fateErr                    error                   // Error from storing the session Fate tracker log
+	chronicles                 []dfm.Chronicle         // Chronicles the user takes part in
+	chroniclesErr              error                   // Error from loading chronicles
+	selectedChronicleIndex     int                     // Index of currently selected chronicle in list
+	chronicleViewMode          ChronicleViewMode       // Current view mode in Chronicles tab (list or detail)
+	selectedChronicle          *dfm.Chronicle          // Currently selected chronicle for detail view
+	chronicleReadme            string                  // Markdown source of the selected chronicle's README
+	chronicleReadmeErr         error                   // Error from loading the README
+	chronicleViewport          viewport.Model          // Scrollable view of the rendered README
+	chronicleResources         []dfm.Resource          // Downloadable files of the selected chronicle
+	chronicleResourcesErr      error                   // Error from loading the downloadable files
+	campaigns                  []dfm.Campaign          // Campaigns the user takes part in
+	campaignsErr               error                   // Error from loading campaigns
+	selectedCampaignIndex      int                     // Index of currently selected campaign in list
+	campaignViewMode           CampaignViewMode        // Current view mode in Campaigns tab
+	selectedCampaign           *dfm.Campaign           // Currently selected campaign for detail view
+	campaignSessions           []dfm.Session           // Sessions of the selected campaign
+	campaignSessionsErr        error                   // Error from loading the sessions
+	selectedCampaignItem       int                     // Selected item in campaign detail (0 is README, then sessions)
+	selectedSession            *dfm.Session            // Session whose notes are open
+	campaignDocument           string                  // Markdown source of the open README or session document
+	campaignDocumentErr        error                   // Error from loading the document
+	campaignViewport           viewport.Model          // Scrollable view of the rendered document
+	campaignResources          []dfm.Resource          // Downloadable files of the selected campaign
+	campaignResourcesErr       error                   // Error from loading the downloadable files
+	activeSessions             []activeSession         // Game sessions open on the server
+	selectedActiveSessionIndex int                     // Index of currently selected session in the Sessions tab
 }
 
-// NewModel creates a new UI model
-func NewModel(username string, backend services.Backend) Model {
+// NewModel creates a new UI model.
+// The session client may be nil, in which case only single-mode rolls are available.
+// The character changes may be nil, in which case characters are loaded only once.
+// serverAddr is the host:port users connect to, shown in resource download commands.
+func NewModel(username string, backend services.Backend, session *services.SessionClient, characterChanges <-chan dfdb.ChangeEvent, serverAddr string) Model {
 	return Model{
 		username:               username,
+		serverAddr:             serverAddr,
 		activeTab:              TabCharacters,
 		backend:                backend,
+		session:                session,
+		characterChanges:       characterChanges,
 		selectedCharacterIndex: 0,                 // Start with first character selected
 		characterViewMode:      CharacterViewList, // Start in list view
 		selectedCharacter:      nil,               // No character selected initially
+		roller:                 dfdice.NewRoller(nil),
+		fateCharacterIndex:     -1, // No character used for rolls until loaded
+		fateSkillIndex:         -1, // Plain roll without skill
+		chronicleViewMode:      ChronicleViewList,
+		characterViewport:      newDocumentViewport(),
+		chronicleViewport:      newDocumentViewport(),
+		campaignViewMode:       CampaignViewList,
+		campaignViewport:       newDocumentViewport(),
 	}
 }
 
 // Init initializes the model (Bubble Tea lifecycle method)
 func (m Model) Init() tea.Cmd {
-	// Load user's characters
-	return loadCharacters(m.username, m.backend)
+	// Load user's data and start listening for character changes, session-mode events and presence changes
+	return tea.Batch(
+		loadCharacters(m.username, m.backend),
+		waitForCharacterChange(m.characterChanges),
+		loadChronicles(m.username, m.backend),
+		loadCampaigns(m.username, m.backend),
+		loadActiveSessions(m.username, m.session, m.backend),
+		waitForSessionEvent(m.session),
+		waitForPresenceChange(m.session),
+	)
 }
 
 // Update handles messages and updates the model (Bubble Tea lifecycle method)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Tab-specific keys take precedence over global navigation
+		switch m.activeTab {
+		case TabCharacters:
+			if updated, cmd, handled := m.updateCharacters(msg); handled {
+				return updated, cmd
+			}
+		case TabSessions:
+			if updated, cmd, handled := m.updateSessions(msg); handled {
+				return updated, cmd
+			}
+		case TabFateTracker:
+			if updated, cmd, handled := m.updateFateTracker(msg); handled {
+				return updated, cmd
+			}
+		case TabChronicles:
+			if updated, cmd, handled := m.updateChronicles(msg); handled {
+				return updated, cmd
+			}
+		case TabCampaigns:
+			if updated, cmd, handled := m.updateCampaigns(msg); handled {
+				return updated, cmd
+			}
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			// Quit the application
@@ -123,7 +243,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "down":
 			// Navigate down in character list (only in Characters tab, list view)
 			if m.activeTab == TabCharacters && m.characterViewMode == CharacterViewList && len(m.characters) > 0 {
-				if m.selectedCharacterIndex < len(m.characters)-1 {
+				if m.selectedCharacterIndex < len(m.listedCharacters())-1 {
 					m.selectedCharacterIndex++
 				}
 			}
@@ -132,9 +252,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			// Select character and switch to detail view (only in Characters tab, list view)
 			if m.activeTab == TabCharacters && m.characterViewMode == CharacterViewList && len(m.characters) > 0 {
-				if m.selectedCharacterIndex >= 0 && m.selectedCharacterIndex < len(m.characters) {
-					m.selectedCharacter = &m.characters[m.selectedCharacterIndex]
+				if i := m.selectedCharacterInList(); i >= 0 {
+					m.selectedCharacter = &m.characters[i]
 					m.characterViewMode = CharacterViewDetail
+					m.characterViewport.GotoTop()
 				}
 			}
 			return m, nil
@@ -144,14 +265,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.activeTab == TabCharacters && m.characterViewMode == CharacterViewDetail {
 				m.characterViewMode = CharacterViewList
 				m.selectedCharacter = nil
+				m.characterSaveErr = nil
 			}
 			return m, nil
 		}
 
+	case tea.MouseMsg:
+		// Mouse wheel scrolls the open character sheet or document
+		if m.activeTab == TabCharacters && m.characterViewMode == CharacterViewDetail && m.selectedCharacter != nil {
+			var cmd tea.Cmd
+			m.characterViewport, cmd = m.characterDetailViewport().Update(msg)
+			return m, cmd
+		}
+		if m.activeTab == TabChronicles && m.chronicleViewMode == ChronicleViewDetail {
+			var cmd tea.Cmd
+			m.chronicleViewport, cmd = m.chronicleViewport.Update(msg)
+			return m, cmd
+		}
+		if m.activeTab == TabCampaigns && m.openCampaignDocumentID() != "" {
+			var cmd tea.Cmd
+			m.campaignViewport, cmd = m.campaignViewport.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		// Handle terminal resize
 		m.width = msg.Width
 		m.height = msg.Height
+		// Re-wrap open documents to the new width
+		if m.chronicleViewMode == ChronicleViewDetail {
+			m.setChronicleReadme()
+		}
+		if m.openCampaignDocumentID() != "" {
+			m.setCampaignDocument()
+		}
 		return m, nil
 
 	case charactersLoadedMsg:
@@ -161,10 +309,142 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Select first character if list is not empty
 		if len(m.characters) > 0 {
 			m.selectedCharacterIndex = 0
+			m.fateCharacterIndex = 0
 		} else {
 			m.selectedCharacterIndex = -1
+			m.fateCharacterIndex = -1
+		}
+		m.fateSkillIndex = -1
+		return m, nil
+
+	case characterSavedMsg:
+		// Edited character stored (or failed)
+		m.characterSaving = false
+		m.characterSaveErr = msg.err
+		if msg.err == nil {
+			m.setSavedCharacter(msg.character)
+		} else {
+			m.setStaleCharacter(msg.err)
+		}
+		return m, nil
+
+	case characterHistoryLoadedMsg:
+		m.setCharacterHistory(msg)
+		return m, nil
+
+	case characterRestoredMsg:
+		return m, m.setRestoredCharacter(msg)
+
+	case characterChangedMsg:
+		// Characters changed in this or another session, or on disk
+		cmds := []tea.Cmd{
+			refreshCharacters(m.username, m.backend),
+			waitForCharacterChange(m.characterChanges),
+		}
+		if m.characterViewMode == CharacterViewHistory {
+			cmds = append(cmds, m.reloadCharacterHistory())
+		}
+		return m, tea.Batch(cmds...)
+
+	case charactersRefreshedMsg:
+		// Keep showing the old list if reloading failed
+		if msg.err == nil {
+			m.setCharacters(msg.characters)
 		}
 		return m, nil
+
+	case characterCreatedMsg:
+		// New character stored (or failed)
+		return m, m.setCreatedCharacter(msg)
+
+	case sessionEventMsg:
+		// Event from another user in the same game session
+		if msg.Type == services.SessionEventRoll {
+			m.rollHistory = append([]rollEntry{{
+				Username:  msg.Username,
+				Character: msg.Character,
+				Result:    msg.Roll,
+			}}, m.rollHistory...)
+			// Keep the scrolled history view stable
+			if m.rollHistoryOffset > 0 {
+				m.rollHistoryOffset++
+			}
+		}
+		if msg.Type == services.SessionEventTurn {
+			m.currentTurn = turnName(msg.Username, msg.Character)
+		}
+		return m, waitForSessionEvent(m.session)
+
+	case chroniclesLoadedMsg:
+		// Chronicles loaded from backend
+		m.chronicles = msg.chronicles
+		m.chroniclesErr = msg.err
+		m.selectedChronicleIndex = 0
+		return m, nil
+
+	case chronicleReadmeLoadedMsg:
+		// Ignore READMEs of chronicles that are no longer open
+		if m.selectedChronicle == nil || m.selectedChronicle.ID != msg.chronicleID {
+			return m, nil
+		}
+		m.chronicleReadme = msg.readme
+		m.chronicleReadmeErr = msg.err
+		m.chronicleResources = msg.resources
+		m.chronicleResourcesErr = msg.resourcesErr
+		m.setChronicleReadme()
+		return m, nil
+
+	case campaignsLoadedMsg:
+		// Campaigns loaded from backend
+		m.campaigns = msg.campaigns
+		m.campaignsErr = msg.err
+		m.selectedCampaignIndex = 0
+		return m, nil
+
+	case campaignSessionsLoadedMsg:
+		// Ignore sessions of campaigns that are no longer open
+		if m.selectedCampaign == nil || m.selectedCampaign.ID != msg.campaignID {
+			return m, nil
+		}
+		m.campaignSessions = msg.sessions
+		m.campaignSessionsErr = msg.err
+		return m, nil
+
+	case campaignDocumentLoadedMsg:
+		// Ignore documents that are no longer open
+		if m.openCampaignDocumentID() != msg.documentID {
+			return m, nil
+		}
+		m.campaignDocument = msg.markdown
+		m.campaignDocumentErr = msg.err
+		m.campaignResources = msg.resources
+		m.campaignResourcesErr = msg.resourcesErr
+		m.setCampaignDocument()
+		return m, nil
+
+	case presenceChangedMsg:
+		// Users connected, disconnected, joined or left game sessions
+		return m, tea.Batch(
+			loadActiveSessions(m.username, m.session, m.backend),
+			waitForPresenceChange(m.session),
+		)
+
+	case activeSessionsLoadedMsg:
+		m.setActiveSessions(msg.sessions)
+		return m, nil
+
+	case sessionJoinedMsg:
+		// Game session joined (or refused)
+		m.sessionJoinErr = msg.err
+		if msg.err == nil {
+			m.currentTurn = ""
+		}
+		return m, nil
+
+	case sessionLogSavedMsg:
+		// Session Fate tracker log entry stored (or failed)
+		m.fateErr = msg.err
+		return m, nil
 	}
 
 	return m, nil
@@ -227,13 +507,13 @@ func (m Model) renderContent() string {
 	case TabCharacters:
 		content = m.renderCharactersTab()
 	case TabSessions:
-		content = "Sessions tab - Not yet implemented"
+		content = m.renderSessionsTab()
 	case TabChronicles:
-		content = "Chronicles tab - Not yet implemented"
+		content = m.renderChroniclesTab()
 	case TabCampaigns:
-		content = "Campaigns tab - Not yet implemented"
+		content = m.renderCampaignsTab()
 	case TabFateTracker:
-		content = "Fate Tracker tab - Not yet implemented"
+		content = m.renderFateTrackerTab()
 	}
 
 	return contentStyle.Render(content)
@@ -249,9 +529,48 @@ func (m Model) renderHelp() string {
 	// Context-sensitive help based on current tab and view mode
 	if m.activeTab == TabCharacters {
 		if m.characterViewMode == CharacterViewList {
-			help = "↑/↓: Navigate | Enter: View Details | Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
+			if m.characterSearch.active {
+				help = "Type to search names, aliases and tags | ↑/↓: Navigate | Enter: Done | ESC: Clear Search"
+			} else {
+				help = "↑/↓: Navigate | Enter: View Details | /: Search | s/g/c: Filter Spirit/Group/Collective | o: Sort | n: New Character | d: Deleted Characters | Tab/→: Next | 1-5: Jump to tab | q: Quit"
+			}
 		} else if m.characterViewMode == CharacterViewDetail {
-			help = "ESC: Back to List | Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
+			help = "e: Edit | v: History | +/-: Gain/Spend Fate Point | p/m/h: Mark Stress | P/M/H: Clear Stress | c/r: Take/Recover Consequence | ↑/↓/j/k: Scroll | PgUp/PgDn: Page | Home/End: Top/Bottom | ESC: Back | q: Quit"
+		} else if m.characterViewMode == CharacterViewHistory {
+			help = "↑/↓/j/k: Navigate | r: Restore Version | ESC: Back | q: Quit"
+		} else if m.characterViewMode == CharacterViewEdit && m.characterConflict.active {
+			help = "r: Reload Their Version | o: Overwrite With Yours | d: Show/Hide Differences | ESC: Back to Editing"
+		} else if m.characterViewMode == CharacterViewEdit {
+			help = "Tab/↓/Enter: Next Field | Shift+Tab/↑: Previous Field | PgUp/PgDn: Jump | Ctrl+S: Save | ESC: Cancel"
+		} else if m.characterViewMode == CharacterViewCreate {
+			if m.characterWizard.step == wizardStepSpirit {
+				help = "↑/↓: Choose Spirit | t: PC/NPC | Enter/Ctrl+N: Next Step | ESC: Cancel"
+			} else {
+				help = "Tab/↓/Enter: Next Field | Shift+Tab/↑: Previous Field | Ctrl+N: Next Step/Create | Ctrl+P: Previous Step | ESC: Cancel"
+			}
+		}
+	} else if m.activeTab == TabSessions {
+		help = "↑/↓: Navigate | Enter: Join Session | l: Leave | Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
+	} else if m.activeTab == TabChronicles {
+		if m.chronicleViewMode == ChronicleViewList {
+			help = "↑/↓: Navigate | Enter: View README | Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
+		} else {
+			help = "↑/↓/j/k: Scroll | PgUp/PgDn: Page | Home/End: Top/Bottom | ESC: Back to List | Tab/→: Next | q: Quit"
+		}
+	} else if m.activeTab == TabCampaigns {
+		switch m.campaignViewMode {
+		case CampaignViewList:
+			help = "↑/↓: Navigate | Enter: Open Campaign | Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
+		case CampaignViewDetail:
+			help = "↑/↓: Navigate | Enter: Open README/Session | ESC: Back to List | Tab/→: Next | q: Quit"
+		default:
+			help = "↑/↓/j/k: Scroll | PgUp/PgDn: Page | Home/End: Top/Bottom | ESC: Back to Campaign | Tab/→: Next | q: Quit"
+		}
+	} else if m.activeTab == TabFateTracker {
+		if m.sessionInputActive {
+			help = "Type game session ID | Enter: Join | ESC: Cancel"
+		} else {
+			help = "r/Space: Roll | ↑/↓: Skill | +/-: Modifier | c: Character | j: Join Session | l: Leave | t: Take Turn | PgUp/PgDn: History | Tab/→: Next | q: Quit"
 		}
 	} else {
 		help = "Tab/→: Next | Shift+Tab/←: Previous | 1-5: Jump to tab | q: Quit"
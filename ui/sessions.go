@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hkionline/dftui/services"
+)
+
+// activeSession is a game session open on the server, as listed in the Sessions tab
+type activeSession struct {
+	ID           string   // Game session ID used in the session hub
+	Name         string   // Session name, or the ID for sessions not stored in the database
+	Campaign     string   // Name of the campaign the session belongs to, if known
+	Gamemaster   string   // Gamemaster of the session, if known
+	Participants []string // Users connected to the session
+}
+
+// presenceChangedMsg is sent when users connect, disconnect, join or leave game sessions
+type presenceChangedMsg struct{}
+
+// activeSessionsLoadedMsg is sent when the active game sessions have been resolved
+type activeSessionsLoadedMsg struct {
+	sessions []activeSession
+}
+
+// waitForPresenceChange waits for the next presence change from the session hub.
+// It returns nil when there is no session client or the client has been closed.
+func waitForPresenceChange(session *services.SessionClient) tea.Cmd {
+	if session == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-session.PresenceChanged(); !ok {
+			return nil
+		}
+		return presenceChangedMsg{}
+	}
+}
+
+// sessionJoinedMsg is sent when joining a game session has been allowed or refused
+type sessionJoinedMsg struct {
+	err error
+}
+
+// joinSession joins a game session if the user may take part in it
+func joinSession(username string, session *services.SessionClient, backend services.Backend, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if err := backend.CanJoinSession(username, sessionID); err != nil {
+			return sessionJoinedMsg{err: fmt.Errorf("cannot join %s: %w", sessionID, err)}
+		}
+		session.Join(sessionID)
+		return sessionJoinedMsg{}
+	}
+}
+
+// loadActiveSessions lists the game sessions open on the server that the user may join
+// and looks up their names, campaigns and gamemasters from the backend
+func loadActiveSessions(username string, session *services.SessionClient, backend services.Backend) tea.Cmd {
+	if session == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		var sessions []activeSession
+		for _, active := range session.ActiveSessions() {
+			// Sessions of other campaigns are not shown
+			if err := backend.CanJoinSession(username, active.ID); err != nil {
+				continue
+			}
+			row := activeSession{
+				ID:           active.ID,
+				Name:         active.ID,
+				Participants: active.Participants,
+			}
+
+			// Ad hoc session IDs have no stored session
+			if stored, err := backend.GetSession(username, active.ID); err == nil {
+				row.Name = stored.Name
+				row.Gamemaster = stored.Gamemaster
+				if campaign, err := backend.GetCampaign(username, stored.CampaignID); err == nil {
+					row.Campaign = campaign.Name
+					if row.Gamemaster == "" {
+						row.Gamemaster = campaign.Gamemaster
+					}
+				}
+			}
+			sessions = append(sessions, row)
+		}
+		return activeSessionsLoadedMsg{sessions: sessions}
+	}
+}
+
+// setActiveSessions replaces the active session list, keeping the selected session selected
+func (m *Model) setActiveSessions(sessions []activeSession) {
+	selectedID := ""
+	if m.selectedActiveSessionIndex >= 0 && m.selectedActiveSessionIndex < len(m.activeSessions) {
+		selectedID = m.activeSessions[m.selectedActiveSessionIndex].ID
+	}
+
+	m.activeSessions = sessions
+	m.selectedActiveSessionIndex = 0
+	for i, session := range sessions {
+		if session.ID == selectedID {
+			m.selectedActiveSessionIndex = i
+			break
+		}
+	}
+}
+
+// updateSessions handles key presses specific to the Sessions tab.
+// It returns false if the key was not handled so global keys keep working.
+func (m Model) updateSessions(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up":
+		if m.selectedActiveSessionIndex > 0 {
+			m.selectedActiveSessionIndex--
+		}
+		return m, nil, true
+
+	case "down":
+		if m.selectedActiveSessionIndex < len(m.activeSessions)-1 {
+			m.selectedActiveSessionIndex++
+		}
+		return m, nil, true
+
+	case "enter":
+		// Join the selected session and switch the Fate Tracker to session-mode
+		if m.session == nil || m.selectedActiveSessionIndex < 0 || m.selectedActiveSessionIndex >= len(m.activeSessions) {
+			return m, nil, true
+		}
+		sessionID := m.activeSessions[m.selectedActiveSessionIndex].ID
+		m.activeTab = TabFateTracker
+		if sessionID != m.sessionID() {
+			return m, joinSession(m.username, m.session, m.backend, sessionID), true
+		}
+		return m, nil, true
+
+	case "l":
+		// Leave the game session and return to single-mode
+		if m.session != nil {
+			m.session.Leave()
+		}
+		m.currentTurn = ""
+		return m, nil, true
+	}
+
+	return m, nil, false
+}
+
+// renderSessionsTab renders the Sessions tab content
+func (m Model) renderSessionsTab() string {
+	if m.session == nil {
+		return "Game sessions are not available"
+	}
+
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var lines []string
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Active game sessions:"))
+	lines = append(lines, "")
+
+	if len(m.activeSessions) == 0 {
+		lines = append(lines, "No active game sessions")
+		lines = append(lines, "")
+		lines = append(lines, hintStyle.Render("Open a session by joining it in the Fate Tracker tab"))
+		return strings.Join(lines, "\n")
+	}
+
+	current := m.sessionID()
+	for i, session := range m.activeSessions {
+		name := session.Name
+		if session.ID == current {
+			name += " (joined)"
+		}
+
+		var details []string
+		if session.Campaign != "" {
+			details = append(details, "Campaign: "+session.Campaign)
+		}
+		if session.Gamemaster != "" {
+			details = append(details, "GM: "+session.Gamemaster)
+		}
+
+		lines = append(lines, renderListItem(name, strings.Join(details, " | "), m.selectedActiveSessionIndex == i))
+		lines = append(lines, hintStyle.Render(fmt.Sprintf("    Connected: %s", strings.Join(session.Participants, ", "))))
+	}
+
+	return strings.Join(lines, "\n")
+}